@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/pressly/goose/v3"
 	files "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/driver/postgres"
@@ -13,22 +20,69 @@ import (
 
 	"go-rest-api/internal/api"
 	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/migrate"
+	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/outbox"
+	"go-rest-api/internal/webhooks"
 )
 
 func main() {
 	cfg := config.MustLoad()
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d",
-		cfg.DB.Host, cfg.DB.Username, cfg.DB.Password, cfg.DB.DBName, cfg.DB.Port)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	migrateCommand := len(os.Args) > 1 && os.Args[1] == "migrate"
+	if !migrateCommand {
+		if err := cfg.Validate(); err != nil {
+			log.Fatalf("Invalid config: %v", err)
+		}
+	}
+
+	dialector, err := newDialector(cfg.DB)
+	if err != nil {
+		log.Fatalf("Failed to configure database: %v", err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{
+		// Нарушения уникальности приходят как gorm.ErrDuplicatedKey.
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	if migrateCommand {
+		if len(os.Args) != 3 {
+			log.Fatal("Usage: go-rest-api migrate up|down|status")
+		}
+		if err := runMigrate(context.Background(), cfg.DB, db, os.Args[2], os.Stdout); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	if cfg.DB.AutoMigrate {
+		if err := runMigrate(context.Background(), cfg.DB, db, "up", log.Default().Writer()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	}
+
 	l := getLogLevel(cfg)
 	logger := slog.New(slog.NewTextHandler(log.Default().Writer(), &slog.HandlerOptions{Level: l}))
 
-	router := api.NewRouter(db, logger)
+	worker := webhooks.NewWorker(repository.NewWebhookRepository(db), cfg.Webhooks.Outgoing, logger)
+	go worker.Run(context.Background())
+
+	sinks, err := newOutboxSinks(cfg, db, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure outbox: %v", err)
+	}
+	dispatcher := outbox.NewDispatcher(repository.NewOutboxRepository(db), sinks, cfg.Outbox, logger)
+	go dispatcher.Run(context.Background())
+
+	go purgeIdempotencyKeys(context.Background(), repository.NewIdempotencyRepository(db), logger)
+
+	router, err := api.NewRouter(cfg, db, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure router: %v", err)
+	}
 	registerCustomError(router)
 	if cfg.EnableSwagger {
 		registerSwagger(router)
@@ -41,6 +95,70 @@ func main() {
 	}
 }
 
+// newDialector выбирает драйвер GORM по db.driver. SQLite открывается с внешними
+// ключами, WAL и транзакциями BEGIN IMMEDIATE: пишущие транзакции сразу берут
+// блокировку базы и ждут друг друга до busy_timeout вместо взаимоблокировки.
+func newDialector(cfg config.DB) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d",
+			cfg.Host, cfg.Username, cfg.Password, cfg.DBName, cfg.Port)
+		return postgres.Open(dsn), nil
+	case config.DriverSQLite:
+		dsn := "file:" + cfg.Path +
+			"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unknown db driver: %s", cfg.Driver)
+	}
+}
+
+// runMigrate выполняет подкоманду migrate (up, down или status) встроенными
+// миграциями и печатает результат в out.
+func runMigrate(ctx context.Context, cfg config.DB, db *gorm.DB, command string, out io.Writer) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	provider, err := migrate.NewProvider(sqlDB, cfg.Driver)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		results, err := provider.Up(ctx)
+		for _, result := range results {
+			fmt.Fprintln(out, result)
+		}
+		if err == nil && len(results) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+		return err
+	case "down":
+		result, err := provider.Down(ctx)
+		if result != nil {
+			fmt.Fprintln(out, result)
+		}
+		return err
+	case "status":
+		statuses, err := provider.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if status.State == goose.StateApplied {
+				appliedAt = status.AppliedAt.UTC().Format(time.DateTime)
+			}
+			fmt.Fprintf(out, "%-19s  %s\n", appliedAt, filepath.Base(status.Source.Path))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, want up, down or status", command)
+	}
+}
+
 func getLogLevel(cfg *config.Config) slog.Level {
 	switch cfg.LogLevel {
 	case "debug":
@@ -56,6 +174,46 @@ func getLogLevel(cfg *config.Config) slog.Level {
 	}
 }
 
+func newOutboxSinks(cfg *config.Config, db *gorm.DB, logger *slog.Logger) ([]outbox.Sink, error) {
+	sinks := make([]outbox.Sink, 0, len(cfg.Outbox.Sinks))
+	for _, name := range cfg.Outbox.Sinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(logger))
+		case "http":
+			if cfg.Outbox.HTTP.URL == "" {
+				return nil, fmt.Errorf("outbox.http.url is required for http sink")
+			}
+			sinks = append(sinks, outbox.NewHTTPSink(cfg.Outbox.HTTP.URL, cfg.Outbox.HTTP.Timeout))
+		case "webhooks":
+			sinks = append(sinks, webhooks.NewPublisher(repository.NewWebhookRepository(db)))
+		default:
+			return nil, fmt.Errorf("unknown outbox sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
+
+// purgeIdempotencyKeys раз в час удаляет истёкшие Idempotency-Key.
+func purgeIdempotencyKeys(ctx context.Context, repo repository.IdempotencyRepository, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := repo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+			logger.Error("failed to purge idempotency keys", "error", err)
+		} else if deleted > 0 {
+			logger.Debug("purged idempotency keys", "count", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func registerSwagger(router *gin.Engine) {
 	router.Static("/docs", "./docs")
 	url := ginSwagger.URL("/docs/openapi.yml")
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditEntry struct {
+	ID         uint64                 `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      string                 `json:"actor"`
+	Operation  string                 `json:"operation"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	TargetIDs  []string               `json:"target_ids"`
+	Diff       map[string]AuditChange `json:"diff"`
+}
+
+type ListAuditRequest struct {
+	EntityType string     `form:"entity_type" binding:"omitempty,oneof=pull_request team user"`
+	EntityID   string     `form:"entity_id"`
+	Actor      string     `form:"actor"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor     string     `form:"cursor"`
+}
+
+type ListAuditResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
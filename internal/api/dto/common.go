@@ -3,12 +3,29 @@ package dto
 type ErrorCode string
 
 const (
-	ErrorCodeTeamExists  ErrorCode = "TEAM_EXISTS"
-	ErrorCodePRExists    ErrorCode = "PR_EXISTS"
-	ErrorCodePRMerged    ErrorCode = "PR_MERGED"
-	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
-	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
-	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
+	ErrorCodeTeamExists         ErrorCode = "TEAM_EXISTS"
+	ErrorCodePRExists           ErrorCode = "PR_EXISTS"
+	ErrorCodePRMerged           ErrorCode = "PR_MERGED"
+	ErrorCodePRClosed           ErrorCode = "PR_CLOSED"
+	ErrorCodeNotAssigned        ErrorCode = "NOT_ASSIGNED"
+	ErrorCodeNoCandidate        ErrorCode = "NO_CANDIDATE"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeInvalidSettings    ErrorCode = "INVALID_SETTINGS"
+	ErrorCodeInvalidCursor      ErrorCode = "INVALID_CURSOR"
+	ErrorCodeInvalidFilter      ErrorCode = "INVALID_FILTER"
+	ErrorCodeInvalidTransition  ErrorCode = "INVALID_TRANSITION"
+	ErrorCodeNotEnoughApprovals ErrorCode = "NOT_ENOUGH_APPROVALS"
+	ErrorCodeInvalidPeriod      ErrorCode = "INVALID_PERIOD"
+	ErrorCodeNotMember          ErrorCode = "NOT_MEMBER"
+	ErrorCodeHasOpenReviews     ErrorCode = "HAS_OPEN_REVIEWS"
+	ErrorCodeTeamHasOpenPRs     ErrorCode = "TEAM_HAS_OPEN_PRS"
+	ErrorCodeInvalidSignature   ErrorCode = "INVALID_SIGNATURE"
+	ErrorCodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodeRateLimited        ErrorCode = "RATE_LIMITED"
+	ErrorCodeIdempotencyReused  ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeIdempotencyPending ErrorCode = "IDEMPOTENCY_IN_PROGRESS"
+	ErrorCodeConcurrentUpdate   ErrorCode = "CONCURRENT_UPDATE"
 )
 
 type ErrorDetail struct {
@@ -7,15 +7,33 @@ type PullRequestStatus string
 const (
 	PullRequestStatusOpen   PullRequestStatus = "OPEN"
 	PullRequestStatusMerged PullRequestStatus = "MERGED"
+	PullRequestStatusClosed PullRequestStatus = "CLOSED"
 )
 
+type ReviewState string
+
+const (
+	ReviewStatePending          ReviewState = "PENDING"
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+)
+
+type Review struct {
+	UserID     string      `json:"user_id"`
+	State      ReviewState `json:"state"`
+	ReviewedAt *time.Time  `json:"reviewedAt,omitempty"`
+}
+
 type PullRequest struct {
 	PullRequestID     string            `json:"pull_request_id"`
 	PullRequestName   string            `json:"pull_request_name"`
 	AuthorID          string            `json:"author_id"`
+	TeamName          string            `json:"team_name,omitempty"`
 	Status            PullRequestStatus `json:"status"`
 	AssignedReviewers []string          `json:"assigned_reviewers"`
+	Reviews           []Review          `json:"reviews,omitempty"`
 	CreatedAt         *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt         *time.Time        `json:"updatedAt,omitempty"`
 	MergedAt          *time.Time        `json:"mergedAt,omitempty"`
 }
 
@@ -24,12 +42,18 @@ type PullRequestShort struct {
 	PullRequestName string            `json:"pull_request_name"`
 	AuthorID        string            `json:"author_id"`
 	Status          PullRequestStatus `json:"status"`
+	CreatedAt       *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt       *time.Time        `json:"updatedAt,omitempty"`
+	MergedAt        *time.Time        `json:"mergedAt,omitempty"`
 }
 
 type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name" binding:"required"`
 	AuthorID        string `json:"author_id" binding:"required"`
+	// TeamName - команда, из которой выбираются ревьюверы. Если не указана,
+	// используется основная команда автора.
+	TeamName string `json:"team_name"`
 }
 
 type CreatePRResponse struct {
@@ -44,6 +68,37 @@ type MergePRResponse struct {
 	PR PullRequest `json:"pr"`
 }
 
+type ClosePRRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+}
+
+type ClosePRResponse struct {
+	PR PullRequest `json:"pr"`
+}
+
+type ReopenPRRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+}
+
+type ReopenPRResponse struct {
+	PR PullRequest `json:"pr"`
+}
+
+type UpdatePRRequest struct {
+	PullRequestID   string `json:"pull_request_id" binding:"required"`
+	PullRequestName string `json:"pull_request_name" binding:"required"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string      `json:"pull_request_id" binding:"required"`
+	ReviewerID    string      `json:"reviewer_id" binding:"required"`
+	State         ReviewState `json:"state" binding:"required,oneof=APPROVED CHANGES_REQUESTED"`
+}
+
+type SubmitReviewResponse struct {
+	PR PullRequest `json:"pr"`
+}
+
 type ReassignPRRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
 	OldUserID     string `json:"old_user_id" binding:"required"`
@@ -58,3 +113,21 @@ type GetUserReviewsResponse struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
 }
+
+type ListPRsRequest struct {
+	Status      string     `form:"status" binding:"omitempty,oneof=OPEN MERGED CLOSED"`
+	AuthorID    string     `form:"author_id"`
+	ReviewerID  string     `form:"reviewer_id"`
+	TeamName    string     `form:"team_name"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	SortBy      string     `form:"sort_by" binding:"omitempty,oneof=id created_at"`
+	Order       string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string     `form:"cursor"`
+}
+
+type ListPRsResponse struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
@@ -23,3 +23,98 @@ type CreateTeamResponse struct {
 type GetTeamResponse struct {
 	Team
 }
+
+type TeamSettings struct {
+	TeamName          string `json:"team_name"`
+	MinReviewers      int    `json:"min_reviewers"`
+	MaxReviewers      int    `json:"max_reviewers"`
+	ReviewerStrategy  string `json:"reviewer_strategy"`
+	RequiredApprovals int    `json:"required_approvals"`
+}
+
+type UpdateTeamSettingsRequest struct {
+	TeamName          string  `json:"team_name" binding:"required"`
+	MinReviewers      *int    `json:"min_reviewers" binding:"omitempty,min=0"`
+	MaxReviewers      *int    `json:"max_reviewers" binding:"omitempty,min=0"`
+	ReviewerStrategy  *string `json:"reviewer_strategy"`
+	RequiredApprovals *int    `json:"required_approvals" binding:"omitempty,min=0"`
+}
+
+type UpdateTeamSettingsResponse struct {
+	Settings TeamSettings `json:"settings"`
+}
+
+type DeactivateTeamUsersRequest struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	UserIDs  []string `json:"user_ids" binding:"required,min=1"`
+}
+
+type ReviewerReplacement struct {
+	OldUserID string `json:"old_user_id"`
+	NewUserID string `json:"new_user_id"`
+}
+
+type PullRequestReassignmentReport struct {
+	PullRequestID     string                `json:"pull_request_id"`
+	Replacements      []ReviewerReplacement `json:"replacements"`
+	NoCandidate       []string              `json:"no_candidate"`
+	AssignedReviewers []string              `json:"assigned_reviewers"`
+}
+
+type DeactivateTeamUsersResponse struct {
+	TeamName         string                          `json:"team_name"`
+	DeactivatedUsers []string                        `json:"deactivated_users"`
+	PullRequests     []PullRequestReassignmentReport `json:"pull_requests"`
+}
+
+// AddTeamMembersRequest добавляет пользователей в команду; для уже существующих
+// пользователей обновляются username и is_active.
+type AddTeamMembersRequest struct {
+	TeamName string       `json:"team_name" binding:"required"`
+	Members  []TeamMember `json:"members" binding:"required,min=1"`
+}
+
+type AddTeamMembersResponse struct {
+	Team Team `json:"team"`
+}
+
+type RemoveTeamMembersRequest struct {
+	TeamName            string   `json:"team_name" binding:"required"`
+	UserIDs             []string `json:"user_ids" binding:"required,min=1"`
+	ReassignOpenReviews bool     `json:"reassign_open_reviews"`
+}
+
+type RemoveTeamMembersResponse struct {
+	Team         Team                            `json:"team"`
+	RemovedUsers []string                        `json:"removed_users"`
+	PullRequests []PullRequestReassignmentReport `json:"pull_requests"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name" binding:"required"`
+	NewTeamName string `json:"new_team_name" binding:"required"`
+}
+
+type RenameTeamResponse struct {
+	Team Team `json:"team"`
+}
+
+// SyncTeamRequest - полный желаемый состав команды.
+type SyncTeamRequest struct {
+	TeamName string       `json:"team_name" binding:"required"`
+	Members  []TeamMember `json:"members" binding:"required"`
+	DryRun   bool         `json:"dry_run"`
+}
+
+type SyncTeamResponse struct {
+	TeamName     string                          `json:"team_name"`
+	DryRun       bool                            `json:"dry_run"`
+	Created      bool                            `json:"created"`
+	Added        []string                        `json:"added"`
+	Removed      []string                        `json:"removed"`
+	Reactivated  []string                        `json:"reactivated"`
+	Deactivated  []string                        `json:"deactivated"`
+	Renamed      []string                        `json:"renamed"`
+	PullRequests []PullRequestReassignmentReport `json:"pull_requests"`
+	Team         *Team                           `json:"team,omitempty"`
+}
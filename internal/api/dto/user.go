@@ -1,17 +1,58 @@
 package dto
 
+import "time"
+
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	TeamName string   `json:"team_name"`
+	Teams    []string `json:"teams"`
+	IsActive bool     `json:"is_active"`
 }
 
 type SetIsActiveRequest struct {
 	UserID   string `json:"user_id" binding:"required"`
 	IsActive bool   `json:"is_active"`
+
+	ReassignOpenReviews bool `json:"reassign_open_reviews"`
+}
+
+type ReviewReassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+type ReassignmentSummary struct {
+	Reassigned  []ReviewReassignment `json:"reassigned"`
+	NoCandidate []string             `json:"no_candidate"`
 }
 
 type SetIsActiveResponse struct {
-	User User `json:"user"`
+	User         User                 `json:"user"`
+	Reassignment *ReassignmentSummary `json:"reassignment,omitempty"`
+}
+
+type Unavailability struct {
+	ID       uint      `json:"id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type AddUnavailabilityRequest struct {
+	UserID   string    `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason" binding:"max=255"`
+}
+
+type AddUnavailabilityResponse struct {
+	Period Unavailability `json:"period"`
+}
+
+type GetUnavailabilityResponse struct {
+	UserID  string           `json:"user_id"`
+	Periods []Unavailability `json:"periods"`
 }
@@ -0,0 +1,94 @@
+package dto
+
+import "time"
+
+type WebhookStatus string
+
+const (
+	WebhookStatusProcessed WebhookStatus = "processed"
+	WebhookStatusIgnored   WebhookStatus = "ignored"
+)
+
+type WebhookResponse struct {
+	Status      WebhookStatus `json:"status"`
+	Reason      string        `json:"reason,omitempty"`
+	PullRequest *PullRequest  `json:"pr,omitempty"`
+}
+
+// GitHubPullRequestEvent - поля события pull_request, которые использует сервис.
+type GitHubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      uint              `json:"number"`
+	PullRequest GitHubPullRequest `json:"pull_request"`
+	Repository  GitHubRepository  `json:"repository"`
+}
+
+type GitHubRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type GitHubPullRequest struct {
+	Number uint       `json:"number"`
+	Title  string     `json:"title"`
+	Merged bool       `json:"merged"`
+	User   GitHubUser `json:"user"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+// GitLabMergeRequestEvent - поля Merge Request Hook, которые использует сервис.
+// User - пользователь, выполнивший действие; для open это автор MR.
+type GitLabMergeRequestEvent struct {
+	ObjectKind       string                  `json:"object_kind"`
+	User             GitLabEventUser         `json:"user"`
+	Project          GitLabProject           `json:"project"`
+	ObjectAttributes GitLabMergeRequestAttrs `json:"object_attributes"`
+}
+
+type GitLabProject struct {
+	ID uint64 `json:"id"`
+}
+
+type GitLabEventUser struct {
+	Username string `json:"username"`
+}
+
+type GitLabMergeRequestAttrs struct {
+	IID    uint   `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Action string `json:"action"`
+}
+
+type GitLabUserMapping struct {
+	Username string `json:"username" binding:"required"`
+	UserID   string `json:"user_id" binding:"required"`
+}
+
+type GetGitLabUsersResponse struct {
+	Users []GitLabUserMapping `json:"users"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=pr.created reviewer.assigned reviewer.reassigned pr.merged team.created team.updated team.deleted"`
+}
+
+// WebhookSubscription - подписка без секрета.
+type WebhookSubscription struct {
+	ID         uint      `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateWebhookSubscriptionResponse struct {
+	Subscription WebhookSubscription `json:"subscription"`
+}
+
+type GetWebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/services"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEntries GET /audit
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	var req dto.ListAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.auditService.ListEntries(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/services"
+)
+
+type GitHubHandler struct {
+	githubService services.GitHubService
+	secret        []byte
+}
+
+func NewGitHubHandler(githubService services.GitHubService, secret string) *GitHubHandler {
+	return &GitHubHandler{
+		githubService: githubService,
+		secret:        []byte(secret),
+	}
+}
+
+// HandleWebhook POST /webhooks/github
+func (h *GitHubHandler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if !verifyGitHubSignature(h.secret, body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeInvalidSignature,
+				Message: "invalid X-Hub-Signature-256",
+			},
+		})
+		return
+	}
+
+	event := c.GetHeader("X-GitHub-Event")
+	if event != "pull_request" {
+		c.JSON(http.StatusOK, dto.WebhookResponse{
+			Status: dto.WebhookStatusIgnored,
+			Reason: "unsupported event: " + event,
+		})
+		return
+	}
+
+	var req dto.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// изменения, пришедшие из вебхука, попадают в журнал от имени источника
+	ctx := auth.WithActor(c.Request.Context(), auth.Actor{ID: "webhook:github"})
+	response, err := h.githubService.HandlePullRequestEvent(ctx, req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusConflict
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// verifyGitHubSignature проверяет заголовок вида sha256=<hex HMAC-SHA256 тела>.
+// Без настроенного секрета все запросы отклоняются.
+func verifyGitHubSignature(secret, body []byte, header string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
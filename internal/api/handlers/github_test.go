@@ -0,0 +1,283 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/api/handlers"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository/memory"
+	"go-rest-api/internal/services"
+)
+
+const testGitHubSecret = "test-secret"
+
+// newWebhookStore создаёт базу в памяти с командой backend из u1, u2 и u3.
+func newWebhookStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	store := memory.NewStore()
+	repos := store.Repositories()
+	teams := services.NewTeamService(store, repos, services.NewReviewerSelectors(repos.PullRequests))
+	_, err := teams.CreateTeam(context.Background(), dto.CreateTeamRequest{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "octocat", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+			{UserID: "u3", Username: "carol", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	return store
+}
+
+func newGitHubRouter(store *memory.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	repos := store.Repositories()
+	githubService := services.NewGitHubService(
+		store,
+		repos,
+		services.NewReviewerSelectors(repos.PullRequests),
+		map[string]string{"octocat": "u1"},
+	)
+	handler := handlers.NewGitHubHandler(githubService, testGitHubSecret)
+
+	router := gin.New()
+	router.POST("/webhooks/github", handler.HandleWebhook)
+	return router
+}
+
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sendGitHubEvent(router *gin.Engine, event string, body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func sendGitHubFixture(t *testing.T, router *gin.Engine, fixture string) dto.WebhookResponse {
+	t.Helper()
+	body := loadFixture(t, filepath.Join("github", fixture))
+	return decodeWebhookResponse(t, sendGitHubEvent(router, "pull_request", body, sign(testGitHubSecret, body)))
+}
+
+func countPullRequests(t *testing.T, store *memory.Store) int {
+	t.Helper()
+	prs, err := store.Repositories().PullRequests.Select(context.Background())
+	if err != nil {
+		t.Fatalf("select PRs: %v", err)
+	}
+	return len(prs)
+}
+
+func TestGitHubWebhook_PullRequestEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      []string
+		fixture    string
+		wantStatus dto.WebhookStatus
+		wantPR     dto.PullRequestStatus
+	}{
+		{"opened", nil, "pull_request_opened.json", dto.WebhookStatusProcessed, dto.PullRequestStatusOpen},
+		{"merged", []string{"pull_request_opened.json"}, "pull_request_closed_merged.json", dto.WebhookStatusProcessed, dto.PullRequestStatusMerged},
+		{"closed", []string{"pull_request_opened.json"}, "pull_request_closed.json", dto.WebhookStatusProcessed, dto.PullRequestStatusClosed},
+		{"reopened", []string{"pull_request_opened.json", "pull_request_closed.json"}, "pull_request_reopened.json", dto.WebhookStatusProcessed, dto.PullRequestStatusOpen},
+		{"unknown author", nil, "pull_request_opened_unknown_user.json", dto.WebhookStatusIgnored, ""},
+		{"unsupported action", nil, "pull_request_labeled.json", dto.WebhookStatusIgnored, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newGitHubRouter(newWebhookStore(t))
+			for _, fixture := range tt.setup {
+				sendGitHubFixture(t, router, fixture)
+			}
+
+			resp := sendGitHubFixture(t, router, tt.fixture)
+			if resp.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q (%s)", resp.Status, tt.wantStatus, resp.Reason)
+			}
+			if tt.wantPR == "" {
+				if resp.PullRequest != nil {
+					t.Errorf("unexpected PR in response: %+v", resp.PullRequest)
+				}
+				return
+			}
+			if resp.PullRequest == nil {
+				t.Fatal("response has no PR")
+			}
+			if resp.PullRequest.Status != tt.wantPR {
+				t.Errorf("PR status = %q, want %q", resp.PullRequest.Status, tt.wantPR)
+			}
+		})
+	}
+}
+
+func TestGitHubWebhook_OpenedMapsAuthorAndRepository(t *testing.T) {
+	store := newWebhookStore(t)
+	router := newGitHubRouter(store)
+
+	resp := sendGitHubFixture(t, router, "pull_request_opened.json")
+	if resp.PullRequest == nil {
+		t.Fatalf("response has no PR: %+v", resp)
+	}
+	if resp.PullRequest.AuthorID != "u1" {
+		t.Errorf("author = %q, want u1", resp.PullRequest.AuthorID)
+	}
+	if resp.PullRequest.PullRequestName != "Add search endpoint" {
+		t.Errorf("title = %q, want %q", resp.PullRequest.PullRequestName, "Add search endpoint")
+	}
+
+	link, err := store.Repositories().ExternalPullRequests.Get(context.Background(), model.ExternalProviderGitHub, "acme/service", 42)
+	if err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+	if got := fmt.Sprintf("pr-%d", link.PrID); got != resp.PullRequest.PullRequestID {
+		t.Errorf("link points to %s, want %s", got, resp.PullRequest.PullRequestID)
+	}
+}
+
+func TestGitHubWebhook_SameNumberInAnotherRepository(t *testing.T) {
+	store := newWebhookStore(t)
+	router := newGitHubRouter(store)
+
+	first := sendGitHubFixture(t, router, "pull_request_opened.json")
+
+	body := bytes.ReplaceAll(loadFixture(t, "github/pull_request_opened.json"), []byte("acme/service"), []byte("acme/other"))
+	second := decodeWebhookResponse(t, sendGitHubEvent(router, "pull_request", body, sign(testGitHubSecret, body)))
+	if second.Status != dto.WebhookStatusProcessed {
+		t.Fatalf("status = %q, want %q (%s)", second.Status, dto.WebhookStatusProcessed, second.Reason)
+	}
+	if second.PullRequest.PullRequestID == first.PullRequest.PullRequestID {
+		t.Errorf("PRs from different repositories share id %s", first.PullRequest.PullRequestID)
+	}
+
+	// закрытие PR во втором репозитории не трогает первый
+	body = bytes.ReplaceAll(loadFixture(t, "github/pull_request_closed.json"), []byte("acme/service"), []byte("acme/other"))
+	closed := decodeWebhookResponse(t, sendGitHubEvent(router, "pull_request", body, sign(testGitHubSecret, body)))
+	if closed.PullRequest.PullRequestID != second.PullRequest.PullRequestID {
+		t.Errorf("closed %s, want %s", closed.PullRequest.PullRequestID, second.PullRequest.PullRequestID)
+	}
+}
+
+func TestGitHubWebhook_MergeSkipsRequiredApprovals(t *testing.T) {
+	store := newWebhookStore(t)
+	repos := store.Repositories()
+	teams := services.NewTeamService(store, repos, services.NewReviewerSelectors(repos.PullRequests))
+	required := 2
+	_, err := teams.UpdateSettings(context.Background(), dto.UpdateTeamSettingsRequest{
+		TeamName:          "backend",
+		RequiredApprovals: &required,
+	})
+	if err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	router := newGitHubRouter(store)
+
+	sendGitHubFixture(t, router, "pull_request_opened.json")
+	resp := sendGitHubFixture(t, router, "pull_request_closed_merged.json")
+	if resp.PullRequest == nil || resp.PullRequest.Status != dto.PullRequestStatusMerged {
+		t.Fatalf("response = %+v, want merged PR", resp)
+	}
+}
+
+func TestGitHubWebhook_UnknownPullRequest(t *testing.T) {
+	router := newGitHubRouter(newWebhookStore(t))
+
+	// GitHub отключает вебхук, доставки которого постоянно падают
+	resp := sendGitHubFixture(t, router, "pull_request_closed.json")
+	if resp.Status != dto.WebhookStatusIgnored || resp.Reason != "PR not found" {
+		t.Errorf("response = %+v, want ignored as PR not found", resp)
+	}
+}
+
+func TestGitHubWebhook_Signature(t *testing.T) {
+	body := loadFixture(t, "github/pull_request_opened.json")
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{"missing", ""},
+		{"wrong secret", sign("other-secret", body)},
+		{"no prefix", sign(testGitHubSecret, body)[len("sha256="):]},
+		{"not hex", "sha256=zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newWebhookStore(t)
+			router := newGitHubRouter(store)
+
+			w := sendGitHubEvent(router, "pull_request", body, tt.signature)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+			if n := countPullRequests(t, store); n != 0 {
+				t.Errorf("PRs created: %d", n)
+			}
+		})
+	}
+}
+
+func TestGitHubWebhook_Redelivery(t *testing.T) {
+	store := newWebhookStore(t)
+	router := newGitHubRouter(store)
+
+	sendGitHubFixture(t, router, "pull_request_opened.json")
+	resp := sendGitHubFixture(t, router, "pull_request_opened.json")
+	if resp.Status != dto.WebhookStatusIgnored {
+		t.Errorf("status = %q, want %q", resp.Status, dto.WebhookStatusIgnored)
+	}
+	if n := countPullRequests(t, store); n != 1 {
+		t.Errorf("PRs = %d, want 1", n)
+	}
+}
+
+func TestGitHubWebhook_Ping(t *testing.T) {
+	store := newWebhookStore(t)
+	router := newGitHubRouter(store)
+	body := loadFixture(t, "github/ping.json")
+
+	w := sendGitHubEvent(router, "ping", body, sign(testGitHubSecret, body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if n := countPullRequests(t, store); n != 0 {
+		t.Errorf("PRs created: %d", n)
+	}
+}
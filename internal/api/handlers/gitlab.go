@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/services"
+)
+
+type GitLabHandler struct {
+	gitlabService services.GitLabService
+	token         []byte
+}
+
+func NewGitLabHandler(gitlabService services.GitLabService, token string) *GitLabHandler {
+	return &GitLabHandler{
+		gitlabService: gitlabService,
+		token:         []byte(token),
+	}
+}
+
+// HandleWebhook POST /webhooks/gitlab
+func (h *GitLabHandler) HandleWebhook(c *gin.Context) {
+	if !h.validToken(c.GetHeader("X-Gitlab-Token")) {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeInvalidSignature,
+				Message: "invalid X-Gitlab-Token",
+			},
+		})
+		return
+	}
+
+	event := c.GetHeader("X-Gitlab-Event")
+	if event != "Merge Request Hook" {
+		c.JSON(http.StatusOK, dto.WebhookResponse{
+			Status: dto.WebhookStatusIgnored,
+			Reason: "unsupported event: " + event,
+		})
+		return
+	}
+
+	var req dto.GitLabMergeRequestEvent
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	// изменения, пришедшие из вебхука, попадают в журнал от имени источника
+	ctx := auth.WithActor(c.Request.Context(), auth.Actor{ID: "webhook:gitlab"})
+	response, err := h.gitlabService.HandleMergeRequestEvent(ctx, c.GetHeader("X-Gitlab-Event-UUID"), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusConflict
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUsers GET /webhooks/gitlab/users
+func (h *GitLabHandler) GetUsers(c *gin.Context) {
+	users, err := h.gitlabService.GetUsers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetGitLabUsersResponse{
+		Users: users,
+	})
+}
+
+// SaveUser POST /webhooks/gitlab/users
+func (h *GitLabHandler) SaveUser(c *gin.Context) {
+	var req dto.GitLabUserMapping
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	user, err := h.gitlabService.SaveUser(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, *user)
+}
+
+// DeleteUser DELETE /webhooks/gitlab/users?username=...
+func (h *GitLabHandler) DeleteUser(c *gin.Context) {
+	username := c.Query("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "username query parameter is required",
+			},
+		})
+		return
+	}
+
+	if err := h.gitlabService.DeleteUser(c.Request.Context(), username); err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// validToken сравнивает X-Gitlab-Token с настроенным; без настроенного токена
+// все запросы отклоняются.
+func (h *GitLabHandler) validToken(token string) bool {
+	if len(h.token) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), h.token) == 1
+}
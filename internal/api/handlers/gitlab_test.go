@@ -0,0 +1,230 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/api/handlers"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository/memory"
+	"go-rest-api/internal/services"
+)
+
+const testGitLabToken = "test-token"
+
+// newGitLabStore - база вебхуков, где GitLab-пользователь jdoe сопоставлен с u1.
+func newGitLabStore(t *testing.T) *memory.Store {
+	t.Helper()
+
+	store := newWebhookStore(t)
+	err := store.Repositories().GitLab.SaveUser(context.Background(), &model.GitLabUser{Username: "jdoe", UserID: 1})
+	if err != nil {
+		t.Fatalf("save GitLab user: %v", err)
+	}
+	return store
+}
+
+func newGitLabRouter(store *memory.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	repos := store.Repositories()
+	gitlabService := services.NewGitLabService(store, repos, services.NewReviewerSelectors(repos.PullRequests))
+	handler := handlers.NewGitLabHandler(gitlabService, testGitLabToken)
+
+	router := gin.New()
+	router.POST("/webhooks/gitlab", handler.HandleWebhook)
+	return router
+}
+
+func sendGitLabEvent(router *gin.Engine, token, eventUUID string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", token)
+	if eventUUID != "" {
+		req.Header.Set("X-Gitlab-Event-UUID", eventUUID)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func sendGitLabFixture(t *testing.T, router *gin.Engine, eventUUID, fixture string) dto.WebhookResponse {
+	t.Helper()
+	body := loadFixture(t, "gitlab/"+fixture)
+	return decodeWebhookResponse(t, sendGitLabEvent(router, testGitLabToken, eventUUID, body))
+}
+
+func decodeWebhookResponse(t *testing.T, w *httptest.ResponseRecorder) dto.WebhookResponse {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp dto.WebhookResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestGitLabWebhook_MergeRequestActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      []string
+		fixture    string
+		wantStatus dto.WebhookStatus
+		wantPR     dto.PullRequestStatus
+		wantTitle  string
+	}{
+		{"open", nil, "merge_request_open.json", dto.WebhookStatusProcessed, dto.PullRequestStatusOpen, "Add search endpoint"},
+		{"update", []string{"merge_request_open.json"}, "merge_request_update.json", dto.WebhookStatusProcessed, dto.PullRequestStatusOpen, "Add search endpoint v2"},
+		{"merge", []string{"merge_request_open.json"}, "merge_request_merge.json", dto.WebhookStatusProcessed, dto.PullRequestStatusMerged, "Add search endpoint"},
+		{"close", []string{"merge_request_open.json"}, "merge_request_close.json", dto.WebhookStatusProcessed, dto.PullRequestStatusClosed, "Add search endpoint"},
+		{"reopen", []string{"merge_request_open.json", "merge_request_close.json"}, "merge_request_reopen.json", dto.WebhookStatusProcessed, dto.PullRequestStatusOpen, "Add search endpoint"},
+		{"unknown user", nil, "merge_request_open_unknown_user.json", dto.WebhookStatusIgnored, "", ""},
+		{"unsupported action", nil, "merge_request_approved.json", dto.WebhookStatusIgnored, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newGitLabRouter(newGitLabStore(t))
+			for _, fixture := range tt.setup {
+				sendGitLabFixture(t, router, "", fixture)
+			}
+
+			resp := sendGitLabFixture(t, router, "", tt.fixture)
+			if resp.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q (%s)", resp.Status, tt.wantStatus, resp.Reason)
+			}
+			if tt.wantPR == "" {
+				if resp.PullRequest != nil {
+					t.Errorf("unexpected PR in response: %+v", resp.PullRequest)
+				}
+				return
+			}
+			if resp.PullRequest == nil {
+				t.Fatal("response has no PR")
+			}
+			if resp.PullRequest.Status != tt.wantPR || resp.PullRequest.PullRequestName != tt.wantTitle {
+				t.Errorf("PR = %s %q, want %s %q", resp.PullRequest.Status, resp.PullRequest.PullRequestName, tt.wantPR, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestGitLabWebhook_OpenMapsAuthorAndProject(t *testing.T) {
+	store := newGitLabStore(t)
+	router := newGitLabRouter(store)
+
+	resp := sendGitLabFixture(t, router, "", "merge_request_open.json")
+	if resp.PullRequest == nil {
+		t.Fatalf("response has no PR: %+v", resp)
+	}
+	if resp.PullRequest.AuthorID != "u1" {
+		t.Errorf("author = %q, want u1", resp.PullRequest.AuthorID)
+	}
+
+	if _, err := store.Repositories().ExternalPullRequests.Get(context.Background(), model.ExternalProviderGitLab, "15", 7); err != nil {
+		t.Fatalf("get link: %v", err)
+	}
+}
+
+func TestGitLabWebhook_SameIIDInAnotherProject(t *testing.T) {
+	store := newGitLabStore(t)
+	router := newGitLabRouter(store)
+
+	first := sendGitLabFixture(t, router, "", "merge_request_open.json")
+
+	body := bytes.Replace(loadFixture(t, "gitlab/merge_request_open.json"), []byte(`"id": 15,`), []byte(`"id": 16,`), 1)
+	second := decodeWebhookResponse(t, sendGitLabEvent(router, testGitLabToken, "", body))
+	if second.Status != dto.WebhookStatusProcessed {
+		t.Fatalf("status = %q, want %q (%s)", second.Status, dto.WebhookStatusProcessed, second.Reason)
+	}
+	if second.PullRequest.PullRequestID == first.PullRequest.PullRequestID {
+		t.Errorf("MRs from different projects share id %s", first.PullRequest.PullRequestID)
+	}
+
+	redelivered := sendGitLabFixture(t, router, "", "merge_request_open.json")
+	if redelivered.Status != dto.WebhookStatusIgnored {
+		t.Errorf("repeated open status = %q, want %q", redelivered.Status, dto.WebhookStatusIgnored)
+	}
+}
+
+func TestGitLabWebhook_Redelivery(t *testing.T) {
+	router := newGitLabRouter(newGitLabStore(t))
+	sendGitLabFixture(t, router, "", "merge_request_open.json")
+	const eventUUID = "5f0b7c1e-8f0a-4a43-9d9e-3c2f1b7d6a10"
+
+	first := sendGitLabFixture(t, router, eventUUID, "merge_request_close.json")
+	if first.Status != dto.WebhookStatusProcessed {
+		t.Fatalf("first delivery status = %q, want %q", first.Status, dto.WebhookStatusProcessed)
+	}
+
+	second := sendGitLabFixture(t, router, eventUUID, "merge_request_close.json")
+	if second.Status != dto.WebhookStatusIgnored || second.Reason != "event already processed" {
+		t.Errorf("redelivery = %q (%s), want ignored as already processed", second.Status, second.Reason)
+	}
+}
+
+func TestGitLabWebhook_RejectedEventsAreIgnored(t *testing.T) {
+	router := newGitLabRouter(newGitLabStore(t))
+
+	// GitLab отключает вебхук, доставки которого постоянно падают
+	for _, fixture := range []string{"merge_request_update.json", "merge_request_close.json"} {
+		resp := sendGitLabFixture(t, router, "", fixture)
+		if resp.Status != dto.WebhookStatusIgnored || resp.Reason != "PR not found" {
+			t.Errorf("%s for unknown MR = %+v, want ignored", fixture, resp)
+		}
+	}
+
+	sendGitLabFixture(t, router, "", "merge_request_open.json")
+	sendGitLabFixture(t, router, "", "merge_request_merge.json")
+	for _, fixture := range []string{"merge_request_update.json", "merge_request_close.json"} {
+		resp := sendGitLabFixture(t, router, "", fixture)
+		if resp.Status != dto.WebhookStatusIgnored || resp.PullRequest != nil {
+			t.Errorf("%s for merged MR = %+v, want ignored", fixture, resp)
+		}
+	}
+}
+
+func TestGitLabWebhook_IgnoredEventIsNotRecorded(t *testing.T) {
+	router := newGitLabRouter(newGitLabStore(t))
+	body := loadFixture(t, "gitlab/merge_request_merge.json")
+	const eventUUID = "0c9d7f2a-1b3e-4c5d-8e6f-7a8b9c0d1e2f"
+
+	first := decodeWebhookResponse(t, sendGitLabEvent(router, testGitLabToken, eventUUID, body))
+	if first.Status != dto.WebhookStatusIgnored {
+		t.Fatalf("status = %q, want %q", first.Status, dto.WebhookStatusIgnored)
+	}
+
+	// событие откатилось вместе с отклонённой операцией, повтор обрабатывается
+	sendGitLabFixture(t, router, "", "merge_request_open.json")
+	resp := decodeWebhookResponse(t, sendGitLabEvent(router, testGitLabToken, eventUUID, body))
+	if resp.Status != dto.WebhookStatusProcessed {
+		t.Errorf("retry status = %q, want %q (%s)", resp.Status, dto.WebhookStatusProcessed, resp.Reason)
+	}
+}
+
+func TestGitLabWebhook_Token(t *testing.T) {
+	body := loadFixture(t, "gitlab/merge_request_open.json")
+
+	for _, token := range []string{"", "wrong-token"} {
+		store := newGitLabStore(t)
+		router := newGitLabRouter(store)
+
+		w := sendGitLabEvent(router, token, "", body)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want %d", token, w.Code, http.StatusUnauthorized)
+		}
+		if n := countPullRequests(t, store); n != 0 {
+			t.Errorf("token %q: PRs created: %d", token, n)
+		}
+	}
+}
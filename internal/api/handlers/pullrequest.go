@@ -38,9 +38,17 @@ func (h *PullRequestHandler) CreatePR(c *gin.Context) {
 		var serviceErr *services.ServiceError
 		if errors.As(err, &serviceErr) {
 			statusCode := http.StatusNotFound
-			if serviceErr.Code == dto.ErrorCodePRExists {
+			if serviceErr.Code == dto.ErrorCodePRExists ||
+				serviceErr.Code == dto.ErrorCodeNoCandidate ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
 				statusCode = http.StatusConflict
 			}
+			if serviceErr.Code == dto.ErrorCodeNotMember {
+				statusCode = http.StatusBadRequest
+			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
 			c.JSON(statusCode, dto.ErrorResponse{
 				Error: dto.ErrorDetail{
 					Code:    serviceErr.Code,
@@ -81,6 +89,14 @@ func (h *PullRequestHandler) MergePR(c *gin.Context) {
 		var serviceErr *services.ServiceError
 		if errors.As(err, &serviceErr) {
 			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodeInvalidTransition ||
+				serviceErr.Code == dto.ErrorCodeNotEnoughApprovals ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
+				statusCode = http.StatusConflict
+			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
 			c.JSON(statusCode, dto.ErrorResponse{
 				Error: dto.ErrorDetail{
 					Code:    serviceErr.Code,
@@ -103,6 +119,100 @@ func (h *PullRequestHandler) MergePR(c *gin.Context) {
 	})
 }
 
+// ClosePR POST /pullRequest/close
+func (h *PullRequestHandler) ClosePR(c *gin.Context) {
+	var req dto.ClosePRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.ClosePR(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodeInvalidTransition ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
+				statusCode = http.StatusConflict
+			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ClosePRResponse{
+		PR: *pr,
+	})
+}
+
+// ReopenPR POST /pullRequest/reopen
+func (h *PullRequestHandler) ReopenPR(c *gin.Context) {
+	var req dto.ReopenPRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.ReopenPR(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodeInvalidTransition ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
+				statusCode = http.StatusConflict
+			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReopenPRResponse{
+		PR: *pr,
+	})
+}
+
 // ReassignReviewer POST /pullRequest/reassign
 func (h *PullRequestHandler) ReassignReviewer(c *gin.Context) {
 	var req dto.ReassignPRRequest
@@ -122,10 +232,102 @@ func (h *PullRequestHandler) ReassignReviewer(c *gin.Context) {
 		if errors.As(err, &serviceErr) {
 			statusCode := http.StatusNotFound
 			if serviceErr.Code == dto.ErrorCodePRMerged ||
+				serviceErr.Code == dto.ErrorCodePRClosed ||
 				serviceErr.Code == dto.ErrorCodeNotAssigned ||
-				serviceErr.Code == dto.ErrorCodeNoCandidate {
+				serviceErr.Code == dto.ErrorCodeNoCandidate ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
 				statusCode = http.StatusConflict
 			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SubmitReview POST /pullRequest/review
+func (h *PullRequestHandler) SubmitReview(c *gin.Context) {
+	var req dto.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodePRMerged ||
+				serviceErr.Code == dto.ErrorCodePRClosed ||
+				serviceErr.Code == dto.ErrorCodeNotAssigned ||
+				serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
+				statusCode = http.StatusConflict
+			}
+			if serviceErr.Code == dto.ErrorCodeForbidden {
+				statusCode = http.StatusForbidden
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SubmitReviewResponse{
+		PR: *pr,
+	})
+}
+
+// ListPRs GET /pullRequest/list
+func (h *PullRequestHandler) ListPRs(c *gin.Context) {
+	var req dto.ListPRsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.prService.ListPRs(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
 			c.JSON(statusCode, dto.ErrorResponse{
 				Error: dto.ErrorDetail{
 					Code:    serviceErr.Code,
@@ -100,3 +100,333 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, *team)
 }
+
+// GetSettings GET /team/settings?team_name=...
+func (h *TeamHandler) GetSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "team_name query parameter is required",
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(c.Request.Context(), teamName)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, *settings)
+}
+
+// UpdateSettings POST /team/settings
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+	var req dto.UpdateTeamSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamService.UpdateSettings(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UpdateTeamSettingsResponse{
+		Settings: *settings,
+	})
+}
+
+// DeactivateUsers POST /team/deactivateUsers
+func (h *TeamHandler) DeactivateUsers(c *gin.Context) {
+	var req dto.DeactivateTeamUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.teamService.DeactivateUsers(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddMembers POST /team/members/add
+func (h *TeamHandler) AddMembers(c *gin.Context) {
+	var req dto.AddTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	team, err := h.teamService.AddMembers(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AddTeamMembersResponse{
+		Team: *team,
+	})
+}
+
+// RemoveMembers POST /team/members/remove
+func (h *TeamHandler) RemoveMembers(c *gin.Context) {
+	var req dto.RemoveTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.teamService.RemoveMembers(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			if serviceErr.Code == dto.ErrorCodeHasOpenReviews {
+				statusCode = http.StatusConflict
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RenameTeam PATCH /team/rename
+func (h *TeamHandler) RenameTeam(c *gin.Context) {
+	var req dto.RenameTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	team, err := h.teamService.RenameTeam(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusBadRequest
+			if serviceErr.Code == dto.ErrorCodeNotFound {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RenameTeamResponse{
+		Team: *team,
+	})
+}
+
+// DeleteTeam DELETE /team?team_name=...
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "team_name query parameter is required",
+			},
+		})
+		return
+	}
+
+	err := h.teamService.DeleteTeam(c.Request.Context(), teamName)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodeTeamHasOpenPRs {
+				statusCode = http.StatusConflict
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SyncTeam PUT /team/sync
+func (h *TeamHandler) SyncTeam(c *gin.Context) {
+	var req dto.SyncTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	response, err := h.teamService.SyncTeam(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
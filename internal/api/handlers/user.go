@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -33,7 +34,7 @@ func (h *UserHandler) SetIsActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.SetIsActive(c.Request.Context(), req)
+	response, err := h.userService.SetIsActive(c.Request.Context(), req)
 	if err != nil {
 		var serviceErr *services.ServiceError
 		if errors.As(err, &serviceErr) {
@@ -55,9 +56,7 @@ func (h *UserHandler) SetIsActive(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SetIsActiveResponse{
-		User: *user,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
 // GetUserReviews GET /users/getReview?user_id=...
@@ -86,3 +85,111 @@ func (h *UserHandler) GetUserReviews(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// AddUnavailability POST /users/unavailability
+func (h *UserHandler) AddUnavailability(c *gin.Context) {
+	var req dto.AddUnavailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	period, err := h.userService.AddUnavailability(c.Request.Context(), req)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			if serviceErr.Code == dto.ErrorCodeInvalidPeriod {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.AddUnavailabilityResponse{
+		Period: *period,
+	})
+}
+
+// GetUnavailability GET /users/unavailability?user_id=...
+func (h *UserHandler) GetUnavailability(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "user_id query parameter is required",
+			},
+		})
+		return
+	}
+
+	response, err := h.userService.GetUnavailability(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteUnavailability DELETE /users/unavailability?user_id=...&id=...
+func (h *UserHandler) DeleteUnavailability(c *gin.Context) {
+	userID := c.Query("user_id")
+	id, err := strconv.ParseUint(c.Query("id"), 10, 32)
+	if userID == "" || err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "user_id and numeric id query parameters are required",
+			},
+		})
+		return
+	}
+
+	if err := h.userService.DeleteUnavailability(c.Request.Context(), userID, uint(id)); err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/services"
+)
+
+type WebhookSubscriptionHandler struct {
+	subscriptionService services.WebhookSubscriptionService
+}
+
+func NewWebhookSubscriptionHandler(subscriptionService services.WebhookSubscriptionService) *WebhookSubscriptionHandler {
+	return &WebhookSubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// CreateSubscription POST /webhooks/subscriptions
+func (h *WebhookSubscriptionHandler) CreateSubscription(c *gin.Context) {
+	var req dto.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	sub, err := h.subscriptionService.CreateSubscription(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateWebhookSubscriptionResponse{
+		Subscription: *sub,
+	})
+}
+
+// GetSubscriptions GET /webhooks/subscriptions
+func (h *WebhookSubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	subs, err := h.subscriptionService.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.GetWebhookSubscriptionsResponse{
+		Subscriptions: subs,
+	})
+}
+
+// DeleteSubscription DELETE /webhooks/subscriptions?id=...
+func (h *WebhookSubscriptionHandler) DeleteSubscription(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Query("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "numeric id query parameter is required",
+			},
+		})
+		return
+	}
+
+	if err := h.subscriptionService.DeleteSubscription(c.Request.Context(), uint(id)); err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			statusCode := http.StatusNotFound
+			c.JSON(statusCode, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    serviceErr.Code,
+					Message: serviceErr.Message,
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "internal server error",
+			},
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+)
+
+// Authenticate требует заголовок Authorization: Bearer <token> и кладёт актора
+// в контекст запроса.
+func Authenticate(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			unauthorized(c, "missing bearer token")
+			return
+		}
+
+		actor, err := authenticator.Authenticate(token)
+		if err != nil {
+			unauthorized(c, "invalid token")
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// RequireAdmin пропускает только актора с ролью admin.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.ActorFromContext(c.Request.Context()).IsAdmin() {
+			forbidden(c, "admin role required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrAdmin пропускает admin и пользователя, чей user_id передан в query
+// параметре param.
+func RequireSelfOrAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := auth.ActorFromContext(c.Request.Context())
+		if !actor.IsAdmin() && actor.ID != c.Query(param) {
+			forbidden(c, "access to another user is forbidden")
+			return
+		}
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+		Error: dto.ErrorDetail{
+			Code:    dto.ErrorCodeUnauthorized,
+			Message: message,
+		},
+	})
+}
+
+func forbidden(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+		Error: dto.ErrorDetail{
+			Code:    dto.ErrorCodeForbidden,
+			Message: message,
+		},
+	})
+}
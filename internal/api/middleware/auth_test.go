@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/middleware"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/config"
+)
+
+func newAuthRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	authenticator, err := auth.NewAuthenticator(config.Auth{
+		Tokens: []config.StaticToken{
+			{Token: "admin-token", Subject: "admin", Role: "admin"},
+			{Token: "user-token", Subject: "u1", Role: "user"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	actor := func(c *gin.Context) {
+		c.String(http.StatusOK, auth.ActorFromContext(c.Request.Context()).ID)
+	}
+
+	router := gin.New()
+	authorized := router.Group("/", middleware.Authenticate(authenticator))
+	authorized.GET("/me", actor)
+	authorized.GET("/reviews", middleware.RequireSelfOrAdmin("user_id"), actor)
+	authorized.POST("/admin", middleware.RequireAdmin(), actor)
+	return router
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	router := newAuthRouter(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"no header", http.MethodGet, "/me", "", http.StatusUnauthorized, ""},
+		{"not bearer", http.MethodGet, "/me", "Basic admin-token", http.StatusUnauthorized, ""},
+		{"unknown token", http.MethodGet, "/me", "Bearer other", http.StatusUnauthorized, ""},
+		{"actor in context", http.MethodGet, "/me", "Bearer user-token", http.StatusOK, "u1"},
+		{"user on admin route", http.MethodPost, "/admin", "Bearer user-token", http.StatusForbidden, ""},
+		{"admin on admin route", http.MethodPost, "/admin", "Bearer admin-token", http.StatusOK, "admin"},
+		{"self", http.MethodGet, "/reviews?user_id=u1", "Bearer user-token", http.StatusOK, "u1"},
+		{"other user", http.MethodGet, "/reviews?user_id=u2", "Bearer user-token", http.StatusForbidden, ""},
+		{"admin for other user", http.MethodGet, "/reviews?user_id=u2", "Bearer admin-token", http.StatusOK, "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header is missing")
+			}
+		})
+	}
+}
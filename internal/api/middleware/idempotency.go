@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/db/model"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore - часть IdempotencyRepository, которая нужна middleware.
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, rec *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, rec *model.IdempotencyKey) error
+	Release(ctx context.Context, scope, key, leaseToken string) error
+}
+
+// Idempotency выполняет POST-запрос с заголовком Idempotency-Key один раз в течение
+// ttl. Повтор с тем же телом получает сохранённый ответ, с другим телом - 422.
+// Ответы 5xx и 409 с кодами временных конфликтов (CONCURRENT_UPDATE,
+// IDEMPOTENCY_IN_PROGRESS) не сохраняются, ключ освобождается и после паники
+// обработчика, такой запрос можно повторить. Пока запрос выполняется, ключ занят только на lease:
+// если экземпляр упал, не освободив ключ, повтор станет возможен по её истечении.
+// Ключи разных клиентов и маршрутов не пересекаются, поэтому в группах с
+// Authenticate middleware подключается после него.
+func Idempotency(store IdempotencyStore, ttl, lease time.Duration, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    dto.ErrorCodeNotFound,
+					Message: "Idempotency-Key is too long",
+				},
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortInternal(c)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := auth.ActorFromContext(c.Request.Context()).ID + " " + c.Request.Method + " " + c.FullPath()
+		hash := requestHash(c.Request.URL.RawQuery, body)
+		leaseToken, err := newLeaseToken()
+		if err != nil {
+			abortInternal(c)
+			return
+		}
+		now := time.Now().UTC()
+		rec := &model.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: hash,
+			LeaseToken:  leaseToken,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(lease),
+		}
+		existing, err := store.Reserve(c.Request.Context(), rec)
+		if err != nil {
+			logger.Error("failed to reserve idempotency key", "error", err)
+			abortInternal(c)
+			return
+		}
+
+		if existing != nil {
+			replay(c, existing, hash)
+			return
+		}
+
+		// ответ уже отправлен клиенту, сохранить его нужно даже при отмене запроса
+		ctx := context.WithoutCancel(c.Request.Context())
+		saved := false
+		defer func() {
+			if saved {
+				return
+			}
+			// 5xx, временный конфликт, паника обработчика или ошибка сохранения:
+			// ответа нет, ключ освобождается для повтора
+			if err := store.Release(ctx, scope, key, leaseToken); err != nil {
+				logger.Error("failed to release idempotency key", "error", err, "scope", scope)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if transientResponse(status, recorder.body.Bytes()) {
+			return
+		}
+		rec.StatusCode = &status
+		rec.ContentType = recorder.Header().Get("Content-Type")
+		rec.ResponseBody = recorder.body.Bytes()
+		rec.ExpiresAt = time.Now().UTC().Add(ttl)
+		if err := store.Complete(ctx, rec); err != nil {
+			logger.Error("failed to save idempotent response", "error", err, "scope", scope)
+			return
+		}
+		saved = true
+	}
+}
+
+func replay(c *gin.Context, existing *model.IdempotencyKey, hash string) {
+	if existing.RequestHash != hash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeIdempotencyReused,
+				Message: "Idempotency-Key was already used with a different request",
+			},
+		})
+		return
+	}
+	if !existing.Completed() {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Error: dto.ErrorDetail{
+				Code:    dto.ErrorCodeIdempotencyPending,
+				Message: "request with this Idempotency-Key is still in progress",
+			},
+		})
+		return
+	}
+
+	c.Header(HeaderIdempotentReplayed, "true")
+	c.Data(*existing.StatusCode, existing.ContentType, existing.ResponseBody)
+	c.Abort()
+}
+
+// transientResponse сообщает, что ответ говорит клиенту повторить запрос и
+// сохранять его нельзя: повтор с тем же ключом получил бы его снова.
+func transientResponse(status int, body []byte) bool {
+	if status >= http.StatusInternalServerError {
+		return true
+	}
+	if status != http.StatusConflict {
+		return false
+	}
+
+	var resp dto.ErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+	switch resp.Error.Code {
+	case dto.ErrorCodeConcurrentUpdate, dto.ErrorCodeIdempotencyPending:
+		return true
+	}
+	return false
+}
+
+// newLeaseToken возвращает случайный токен резервирования ключа.
+func newLeaseToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func requestHash(query string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func abortInternal(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error: dto.ErrorDetail{
+			Code:    dto.ErrorCodeNotFound,
+			Message: "internal server error",
+		},
+	})
+}
+
+// responseRecorder копирует тело ответа, продолжая писать его клиенту.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,287 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/api/middleware"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/db/model"
+)
+
+// memoryIdempotencyStore хранит ключи в памяти вместо idempotency_keys.
+type memoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]*model.IdempotencyKey
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: map[string]*model.IdempotencyKey{}}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, rec *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := rec.Scope + "|" + rec.Key
+	if existing, ok := s.keys[id]; ok && existing.ExpiresAt.After(rec.CreatedAt) {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *rec
+	s.keys[id] = &copied
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, rec *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.keys[rec.Scope+"|"+rec.Key]
+	if !ok || stored.Completed() || stored.RequestHash != rec.RequestHash || stored.LeaseToken != rec.LeaseToken {
+		return errors.New("key is not reserved by this request")
+	}
+	status := *rec.StatusCode
+	stored.StatusCode, stored.ContentType, stored.ResponseBody = &status, rec.ContentType, append([]byte(nil), rec.ResponseBody...)
+	stored.ExpiresAt = rec.ExpiresAt
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, scope, key, leaseToken string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.keys[scope+"|"+key]; ok && !stored.Completed() && stored.LeaseToken == leaseToken {
+		delete(s.keys, scope+"|"+key)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) forget(scope, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[scope+"|"+key].StatusCode = nil
+}
+
+func (s *memoryIdempotencyStore) get(scope, key string) (model.IdempotencyKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.keys[scope+"|"+key]
+	if !ok {
+		return model.IdempotencyKey{}, false
+	}
+	return *rec, true
+}
+
+type idempotencyFixture struct {
+	router *gin.Engine
+	store  *memoryIdempotencyStore
+	calls  int
+	status int
+	// code - код ошибки в теле ответа; без него обработчик отвечает успехом
+	code dto.ErrorCode
+	// onCall вызывается обработчиком перед ответом
+	onCall func()
+}
+
+func newIdempotencyFixture(t *testing.T) *idempotencyFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	f := &idempotencyFixture{store: newMemoryIdempotencyStore(), status: http.StatusCreated}
+	f.router = gin.New()
+	f.router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), auth.Actor{ID: c.GetHeader("X-Test-Subject")}))
+	})
+	f.router.Use(gin.RecoveryWithWriter(io.Discard))
+	f.router.Use(middleware.Idempotency(f.store, time.Hour, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	handler := func(c *gin.Context) {
+		f.calls++
+		if f.onCall != nil {
+			f.onCall()
+		}
+		if f.code != "" {
+			c.JSON(f.status, dto.ErrorResponse{Error: dto.ErrorDetail{Code: f.code, Message: "error"}})
+			return
+		}
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(f.status, gin.H{"call": f.calls, "body": string(body)})
+	}
+	f.router.POST("/pullRequest/reassign", handler)
+	f.router.GET("/pullRequest/list", handler)
+	return f
+}
+
+func (f *idempotencyFixture) do(method, body, key, subject string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/pullRequest/"+map[string]string{
+		http.MethodPost: "reassign",
+		http.MethodGet:  "list",
+	}[method], strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+	}
+	req.Header.Set("X-Test-Subject", subject)
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	f := newIdempotencyFixture(t)
+	body := `{"pull_request_id":"pr-1","old_user_id":"u2"}`
+
+	first := f.do(http.MethodPost, body, "k1", "ci")
+	if first.Code != http.StatusCreated || first.Header().Get(middleware.HeaderIdempotentReplayed) != "" {
+		t.Fatalf("first = %d %v", first.Code, first.Header())
+	}
+
+	retry := f.do(http.MethodPost, body, "k1", "ci")
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry = %d %s, want %s", retry.Code, retry.Body, first.Body)
+	}
+	if retry.Header().Get(middleware.HeaderIdempotentReplayed) != "true" ||
+		!strings.HasPrefix(retry.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("retry headers = %v", retry.Header())
+	}
+	if f.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", f.calls)
+	}
+
+	// тот же ключ другого клиента - отдельный запрос
+	if rec := f.do(http.MethodPost, body, "k1", "dev"); rec.Header().Get(middleware.HeaderIdempotentReplayed) != "" || f.calls != 2 {
+		t.Errorf("other subject replayed: calls = %d", f.calls)
+	}
+}
+
+func TestIdempotency_RejectsDifferentBody(t *testing.T) {
+	f := newIdempotencyFixture(t)
+
+	f.do(http.MethodPost, `{"old_user_id":"u2"}`, "k1", "ci")
+	rec := f.do(http.MethodPost, `{"old_user_id":"u3"}`, "k1", "ci")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Fatalf("different body = %d %s", rec.Code, rec.Body)
+	}
+	if f.calls != 1 {
+		t.Errorf("handler calls = %d, want 1", f.calls)
+	}
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	f := newIdempotencyFixture(t)
+
+	f.do(http.MethodPost, `{}`, "k1", "ci")
+	f.store.forget("ci POST /pullRequest/reassign", "k1")
+
+	rec := f.do(http.MethodPost, `{}`, "k1", "ci")
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_IN_PROGRESS") {
+		t.Fatalf("in progress = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	f := newIdempotencyFixture(t)
+
+	f.status = http.StatusInternalServerError
+	f.do(http.MethodPost, `{}`, "k1", "ci")
+
+	f.status = http.StatusOK
+	rec := f.do(http.MethodPost, `{}`, "k1", "ci")
+	if rec.Code != http.StatusOK || f.calls != 2 {
+		t.Fatalf("retry after 500 = %d, calls = %d", rec.Code, f.calls)
+	}
+}
+
+func TestIdempotency_TransientConflictsAreNotStored(t *testing.T) {
+	for _, code := range []dto.ErrorCode{dto.ErrorCodeConcurrentUpdate, dto.ErrorCodeIdempotencyPending} {
+		t.Run(string(code), func(t *testing.T) {
+			f := newIdempotencyFixture(t)
+
+			f.status, f.code = http.StatusConflict, code
+			f.do(http.MethodPost, `{}`, "k1", "ci")
+
+			f.status, f.code = http.StatusOK, ""
+			rec := f.do(http.MethodPost, `{}`, "k1", "ci")
+			if rec.Code != http.StatusOK || f.calls != 2 {
+				t.Fatalf("retry after %s = %d, calls = %d", code, rec.Code, f.calls)
+			}
+		})
+	}
+
+	// остальные 409 - результат запроса, повтор получает его же
+	f := newIdempotencyFixture(t)
+	f.status, f.code = http.StatusConflict, dto.ErrorCodePRMerged
+	f.do(http.MethodPost, `{}`, "k1", "ci")
+	f.status, f.code = http.StatusOK, ""
+	rec := f.do(http.MethodPost, `{}`, "k1", "ci")
+	if rec.Code != http.StatusConflict || f.calls != 1 {
+		t.Errorf("retry after PR_MERGED = %d, calls = %d, want replayed 409", rec.Code, f.calls)
+	}
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	f := newIdempotencyFixture(t)
+
+	f.onCall = func() { panic("boom") }
+	if rec := f.do(http.MethodPost, `{}`, "k1", "ci"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panic = %d, want 500", rec.Code)
+	}
+	if _, ok := f.store.get("ci POST /pullRequest/reassign", "k1"); ok {
+		t.Fatal("key is still reserved after panic")
+	}
+
+	f.onCall = nil
+	rec := f.do(http.MethodPost, `{}`, "k1", "ci")
+	if rec.Code != http.StatusCreated || f.calls != 2 {
+		t.Fatalf("retry after panic = %d, calls = %d", rec.Code, f.calls)
+	}
+}
+
+func TestIdempotency_PendingKeyHasShortLease(t *testing.T) {
+	f := newIdempotencyFixture(t)
+	const scope = "ci POST /pullRequest/reassign"
+
+	start := time.Now().UTC()
+	f.onCall = func() {
+		rec, ok := f.store.get(scope, "k1")
+		if !ok || rec.Completed() {
+			t.Errorf("pending key = %+v, %v", rec, ok)
+			return
+		}
+		if rec.ExpiresAt.After(start.Add(2 * time.Minute)) {
+			t.Errorf("pending key expires at %v, want within the lease", rec.ExpiresAt)
+		}
+	}
+	f.do(http.MethodPost, `{}`, "k1", "ci")
+
+	rec, _ := f.store.get(scope, "k1")
+	if !rec.Completed() || rec.ExpiresAt.Before(start.Add(time.Hour)) {
+		t.Errorf("completed key = %+v, want TTL from completion", rec)
+	}
+}
+
+func TestIdempotency_PassThrough(t *testing.T) {
+	f := newIdempotencyFixture(t)
+
+	f.do(http.MethodPost, `{}`, "", "ci")
+	f.do(http.MethodPost, `{}`, "", "ci")
+	f.do(http.MethodGet, ``, "k1", "ci")
+	f.do(http.MethodGet, ``, "k1", "ci")
+	if f.calls != 4 {
+		t.Errorf("handler calls = %d, want 4", f.calls)
+	}
+
+	rec := f.do(http.MethodPost, `{}`, strings.Repeat("k", 256), "ci")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("long key = %d, want 400", rec.Code)
+	}
+}
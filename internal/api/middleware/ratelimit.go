@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/ratelimit"
+)
+
+// RateLimit ограничивает запросы клиента: аутентифицированного - по subject токена,
+// остальных - по IP. Поэтому в группах с Authenticate подключается после него.
+// Если хранилище лимитов недоступно, запрос пропускается.
+func RateLimit(limiter *ratelimit.Limiter, logger *slog.Logger) gin.HandlerFunc {
+	return rateLimit(limiter, logger, clientKey)
+}
+
+// RateLimitByIP ограничивает запросы по IP клиента независимо от токена. Ставится
+// перед Authenticate, чтобы запросы с неверными токенами тоже расходовали лимит.
+func RateLimitByIP(limiter *ratelimit.Limiter, logger *slog.Logger) gin.HandlerFunc {
+	return rateLimit(limiter, logger, func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	})
+}
+
+func rateLimit(limiter *ratelimit.Limiter, logger *slog.Logger, key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), key(c), c.Request.Method, c.FullPath())
+		if err != nil {
+			logger.Error("rate limiter unavailable", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error: dto.ErrorDetail{
+					Code:    dto.ErrorCodeRateLimited,
+					Message: "rate limit exceeded",
+				},
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	actor := auth.ActorFromContext(c.Request.Context())
+	if actor.ID != auth.AnonymousActorID {
+		return "sub:" + actor.ID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
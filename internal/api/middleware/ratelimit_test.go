@@ -0,0 +1,129 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go-rest-api/internal/api/middleware"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is down")
+}
+
+func newRateLimitRouter(t *testing.T, store ratelimit.Store) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	limiter, err := ratelimit.NewLimiter(store, config.RateLimit{
+		Default: config.RateLimitRule{RPS: 0.001, Burst: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Test-Subject"); subject != "" {
+			c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), auth.Actor{ID: subject}))
+		}
+	})
+	router.Use(middleware.RateLimit(limiter, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return router
+}
+
+func doPing(router *gin.Engine, subject, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = ip + ":1234"
+	if subject != "" {
+		req.Header.Set("X-Test-Subject", subject)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	router := newRateLimitRouter(t, ratelimit.NewMemoryStore())
+
+	first := doPing(router, "ci", "10.0.0.1")
+	if first.Code != http.StatusOK || first.Header().Get("X-RateLimit-Limit") != "2" ||
+		first.Header().Get("X-RateLimit-Remaining") != "1" || first.Header().Get("X-RateLimit-Reset") == "" {
+		t.Fatalf("first = %d %v", first.Code, first.Header())
+	}
+
+	// subject один и тот же, IP другой - корзина общая
+	if rec := doPing(router, "ci", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Fatalf("second = %d", rec.Code)
+	}
+	limited := doPing(router, "ci", "10.0.0.3")
+	if limited.Code != http.StatusTooManyRequests {
+		t.Fatalf("third = %d, want 429", limited.Code)
+	}
+	if limited.Header().Get("Retry-After") == "" || limited.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("429 headers = %v", limited.Header())
+	}
+
+	// анонимные клиенты считаются по IP
+	if rec := doPing(router, "", "10.0.0.3"); rec.Code != http.StatusOK {
+		t.Errorf("anonymous = %d", rec.Code)
+	}
+}
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	router := newRateLimitRouter(t, failingStore{})
+
+	if rec := doPing(router, "ci", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 when store is down", rec.Code)
+	}
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), config.RateLimit{
+		Default: config.RateLimitRule{RPS: 0.001, Burst: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	router.Use(middleware.RateLimitByIP(limiter, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusUnauthorized, "bad token") })
+
+	// разные токены и подставленный X-Forwarded-For не дают новой корзины
+	for i, subject := range []string{"a", "b", "c"} {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("Authorization", "Bearer "+subject)
+		req.Header.Set("X-Forwarded-For", "192.0.2."+strconv.Itoa(i))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		want := http.StatusUnauthorized
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Errorf("request %d = %d, want %d", i, rec.Code, want)
+		}
+	}
+}
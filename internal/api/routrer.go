@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
@@ -8,37 +9,120 @@ import (
 	"gorm.io/gorm"
 
 	"go-rest-api/internal/api/handlers"
+	"go-rest-api/internal/api/middleware"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/config"
+	dbtx "go-rest-api/internal/db"
 	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/ratelimit"
 	"go-rest-api/internal/services"
 )
 
-func NewRouter(db *gorm.DB, logger *slog.Logger) *gin.Engine {
+func NewRouter(cfg *config.Config, db *gorm.DB, logger *slog.Logger) (*gin.Engine, error) {
+	authenticator, err := auth.NewAuthenticator(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit := func(c *gin.Context) { c.Next() }
+	ipRateLimit := rateLimit
+	if cfg.RateLimit.Enabled {
+		limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		rateLimit = middleware.RateLimit(limiter, logger)
+
+		ipLimiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), config.RateLimit{Default: cfg.RateLimit.IP})
+		if err != nil {
+			return nil, fmt.Errorf("rate_limit.ip: %w", err)
+		}
+		ipRateLimit = middleware.RateLimitByIP(ipLimiter, logger)
+	}
+
 	router := gin.Default()
+	// без доверенных прокси X-Forwarded-For игнорируется, иначе клиент мог бы
+	// подставить в нём любой IP и обойти лимиты
+	if err := router.SetTrustedProxies(cfg.HTTPServer.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("http_server.trusted_proxies: %w", err)
+	}
+
+	repos := dbtx.NewRepositories(db)
+	uow := dbtx.NewTxManager(db, cfg.DB.TxRetry)
+	prRepo := repos.PullRequests
+	webhookRepo := repository.NewWebhookRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
 
-	userRepo := repository.NewUserRepository(db)
-	teamRepo := repository.NewTeamRepository(db)
-	prRepo := repository.NewPullRequestRepository(db)
+	reviewerSelectors := services.NewReviewerSelectors(prRepo)
 
-	teamService := services.NewTeamService(db, teamRepo, userRepo)
-	userService := services.NewUserService(db, userRepo, prRepo)
-	prService := services.NewPullRequestService(db, prRepo, userRepo, teamRepo)
+	teamService := services.NewTeamService(uow, repos, reviewerSelectors)
+	userService := services.NewUserService(uow, repos, reviewerSelectors)
+	prService := services.NewPullRequestService(uow, repos, reviewerSelectors)
+	githubService := services.NewGitHubService(uow, repos, reviewerSelectors, cfg.Webhooks.GitHub.Users)
+	gitlabService := services.NewGitLabService(uow, repos, reviewerSelectors)
+	subscriptionService := services.NewWebhookSubscriptionService(webhookRepo)
+	auditService := services.NewAuditService(repos.Audit)
 
 	teamHandler := handlers.NewTeamHandler(teamService)
 	userHandler := handlers.NewUserHandler(userService)
 	prHandler := handlers.NewPullRequestHandler(prService)
+	githubHandler := handlers.NewGitHubHandler(githubService, cfg.Webhooks.GitHub.Secret)
+	gitlabHandler := handlers.NewGitLabHandler(gitlabService, cfg.Webhooks.GitLab.Token)
+	subscriptionHandler := handlers.NewWebhookSubscriptionHandler(subscriptionService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 
 	router.Use(sloggin.New(logger))
 	router.Use(gin.Recovery())
 
-	router.POST("/team/add", teamHandler.CreateTeam)
-	router.GET("/team/get", teamHandler.GetTeam)
+	// вебхуки аутентифицируются собственной подписью
+	if cfg.Webhooks.GitHub.Enabled {
+		router.POST("/webhooks/github", rateLimit, githubHandler.HandleWebhook)
+	}
+	if cfg.Webhooks.GitLab.Enabled {
+		router.POST("/webhooks/gitlab", rateLimit, gitlabHandler.HandleWebhook)
+	}
+
+	authorized := router.Group("/",
+		ipRateLimit,
+		middleware.Authenticate(authenticator),
+		rateLimit,
+		middleware.Idempotency(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.PendingLease, logger),
+	)
+	admin := authorized.Group("/", middleware.RequireAdmin())
+
+	authorized.GET("/team/get", teamHandler.GetTeam)
+	authorized.GET("/team/settings", teamHandler.GetSettings)
+	admin.POST("/team/add", teamHandler.CreateTeam)
+	admin.POST("/team/settings", teamHandler.UpdateSettings)
+	admin.POST("/team/deactivateUsers", teamHandler.DeactivateUsers)
+	admin.POST("/team/members/add", teamHandler.AddMembers)
+	admin.POST("/team/members/remove", teamHandler.RemoveMembers)
+	admin.PATCH("/team/rename", teamHandler.RenameTeam)
+	admin.DELETE("/team", teamHandler.DeleteTeam)
+	admin.PUT("/team/sync", teamHandler.SyncTeam)
+
+	authorized.GET("/users/getReview", middleware.RequireSelfOrAdmin("user_id"), userHandler.GetUserReviews)
+	authorized.GET("/users/unavailability", middleware.RequireSelfOrAdmin("user_id"), userHandler.GetUnavailability)
+	admin.POST("/users/setIsActive", userHandler.SetIsActive)
+	admin.POST("/users/unavailability", userHandler.AddUnavailability)
+	admin.DELETE("/users/unavailability", userHandler.DeleteUnavailability)
+
+	authorized.POST("/pullRequest/create", prHandler.CreatePR)
+	authorized.POST("/pullRequest/close", prHandler.ClosePR)
+	authorized.POST("/pullRequest/reopen", prHandler.ReopenPR)
+	authorized.POST("/pullRequest/reassign", prHandler.ReassignReviewer)
+	authorized.POST("/pullRequest/review", prHandler.SubmitReview)
+	authorized.GET("/pullRequest/list", prHandler.ListPRs)
+	admin.POST("/pullRequest/merge", prHandler.MergePR)
 
-	router.POST("/users/setIsActive", userHandler.SetIsActive)
-	router.GET("/users/getReview", userHandler.GetUserReviews)
+	admin.GET("/webhooks/gitlab/users", gitlabHandler.GetUsers)
+	admin.POST("/webhooks/gitlab/users", gitlabHandler.SaveUser)
+	admin.DELETE("/webhooks/gitlab/users", gitlabHandler.DeleteUser)
+	admin.GET("/webhooks/subscriptions", subscriptionHandler.GetSubscriptions)
+	admin.POST("/webhooks/subscriptions", subscriptionHandler.CreateSubscription)
+	admin.DELETE("/webhooks/subscriptions", subscriptionHandler.DeleteSubscription)
 
-	router.POST("/pullRequest/create", prHandler.CreatePR)
-	router.POST("/pullRequest/merge", prHandler.MergePR)
-	router.POST("/pullRequest/reassign", prHandler.ReassignReviewer)
+	admin.GET("/audit", auditHandler.ListEntries)
 
-	return router
+	return router, nil
 }
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-rest-api/internal/config"
+)
+
+var ErrUnknownToken = errors.New("unknown token")
+
+type staticToken struct {
+	hash  [sha256.Size]byte
+	actor Actor
+}
+
+// Authenticator проверяет bearer-токены: сначала статические из конфига, затем,
+// если задан секрет, JWT с подписью HS256.
+type Authenticator struct {
+	tokens    []staticToken
+	jwtSecret []byte
+	jwtIssuer string
+	now       func() time.Time
+}
+
+func NewAuthenticator(cfg config.Auth) (*Authenticator, error) {
+	a := &Authenticator{
+		jwtSecret: []byte(cfg.JWT.Secret),
+		jwtIssuer: cfg.JWT.Issuer,
+		now:       time.Now,
+	}
+
+	for i, token := range cfg.Tokens {
+		role := Role(token.Role)
+		if token.Token == "" || token.Subject == "" {
+			return nil, fmt.Errorf("auth.tokens[%d]: token and subject are required", i)
+		}
+		if !IsKnownRole(role) {
+			return nil, fmt.Errorf("auth.tokens[%d]: unknown role %q", i, token.Role)
+		}
+		a.tokens = append(a.tokens, staticToken{
+			hash:  sha256.Sum256([]byte(token.Token)),
+			actor: Actor{ID: token.Subject, Role: role},
+		})
+	}
+
+	return a, nil
+}
+
+func (a *Authenticator) Authenticate(token string) (Actor, error) {
+	// сравниваем хэши, чтобы время сравнения не зависело от длины и содержимого токена
+	hash := sha256.Sum256([]byte(token))
+	for _, static := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], static.hash[:]) == 1 {
+			return static.actor, nil
+		}
+	}
+
+	if len(a.jwtSecret) == 0 || strings.Count(token, ".") != 2 {
+		return Actor{}, ErrUnknownToken
+	}
+
+	claims, err := ParseHS256(token, a.jwtSecret, a.jwtIssuer, a.now())
+	if err != nil {
+		return Actor{}, err
+	}
+	if claims.Subject == "" {
+		return Actor{}, errors.New("token has no subject")
+	}
+	if !IsKnownRole(claims.Role) {
+		return Actor{}, fmt.Errorf("unknown role %q", claims.Role)
+	}
+	return Actor{ID: claims.Subject, Role: claims.Role}, nil
+}
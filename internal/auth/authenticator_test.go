@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/config"
+)
+
+func newTestAuthenticator(t *testing.T, now time.Time) *Authenticator {
+	t.Helper()
+	a, err := NewAuthenticator(config.Auth{
+		Tokens: []config.StaticToken{
+			{Token: "admin-token", Subject: "admin", Role: "admin"},
+			{Token: "user-token", Subject: "u1", Role: "user"},
+		},
+		JWT: config.JWTAuth{Secret: "secret", Issuer: "ci"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func TestAuthenticator_StaticTokens(t *testing.T) {
+	a := newTestAuthenticator(t, time.Now())
+
+	actor, err := a.Authenticate("admin-token")
+	if err != nil || actor != (Actor{ID: "admin", Role: RoleAdmin}) {
+		t.Errorf("admin-token = %+v, %v", actor, err)
+	}
+	actor, err = a.Authenticate("user-token")
+	if err != nil || actor != (Actor{ID: "u1", Role: RoleUser}) {
+		t.Errorf("user-token = %+v, %v", actor, err)
+	}
+	if _, err := a.Authenticate("other-token"); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("other-token err = %v, want ErrUnknownToken", err)
+	}
+}
+
+func TestAuthenticator_JWT(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAuthenticator(t, now)
+
+	sign := func(claims Claims, secret string) string {
+		token, err := SignHS256(claims, []byte(secret))
+		if err != nil {
+			t.Fatalf("SignHS256: %v", err)
+		}
+		return token
+	}
+	valid := Claims{Subject: "u7", Role: RoleUser, Issuer: "ci", ExpiresAt: now.Add(time.Minute).Unix()}
+
+	actor, err := a.Authenticate(sign(valid, "secret"))
+	if err != nil || actor != (Actor{ID: "u7", Role: RoleUser}) {
+		t.Fatalf("valid token = %+v, %v", actor, err)
+	}
+
+	expired := valid
+	expired.ExpiresAt = now.Add(-time.Second).Unix()
+	notYet := valid
+	notYet.NotBefore = now.Add(time.Minute).Unix()
+	otherIssuer := valid
+	otherIssuer.Issuer = "someone"
+	noRole := valid
+	noRole.Role = ""
+
+	tampered := sign(valid, "secret")
+	parts := strings.Split(tampered, ".")
+	adminPayload := strings.Split(sign(Claims{Subject: "u7", Role: RoleAdmin, Issuer: "ci"}, "secret"), ".")[1]
+	tampered = parts[0] + "." + adminPayload + "." + parts[2]
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"wrong secret", sign(valid, "other"), ErrInvalidSignature},
+		{"tampered payload", tampered, ErrInvalidSignature},
+		{"expired", sign(expired, "secret"), ErrTokenExpired},
+		{"not yet valid", sign(notYet, "secret"), ErrTokenNotYetValid},
+		{"other issuer", sign(otherIssuer, "secret"), ErrInvalidIssuer},
+		{"malformed", "a.b.c", ErrMalformedToken},
+		{"no role", sign(noRole, "secret"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.Authenticate(tt.token)
+			if err == nil {
+				t.Fatal("token accepted")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHS256_RejectsOtherAlgorithms(t *testing.T) {
+	// {"alg":"none"}.{"sub":"admin","role":"admin"}.
+	token := "eyJhbGciOiJub25lIn0.eyJzdWIiOiJhZG1pbiIsInJvbGUiOiJhZG1pbiJ9."
+	if _, err := ParseHS256(token, []byte("secret"), "", time.Now()); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Errorf("err = %v, want ErrUnsupportedAlg", err)
+	}
+}
+
+func TestNewAuthenticator_ValidatesTokens(t *testing.T) {
+	_, err := NewAuthenticator(config.Auth{
+		Tokens: []config.StaticToken{{Token: "t", Subject: "s", Role: "root"}},
+	})
+	if err == nil {
+		t.Error("unknown role accepted")
+	}
+}
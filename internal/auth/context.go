@@ -0,0 +1,42 @@
+// Package auth хранит в контексте запроса того, кто выполняет операцию.
+package auth
+
+import "context"
+
+// AnonymousActorID - актор запросов без аутентификации.
+const AnonymousActorID = "anonymous"
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+func IsKnownRole(role Role) bool {
+	return role == RoleAdmin || role == RoleUser
+}
+
+// Actor - аутентифицированный клиент. Для роли user ID - это user_id пользователя.
+type Actor struct {
+	ID   string
+	Role Role
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает актора запроса или анонимного, если он не задан.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok && actor.ID != "" {
+		return actor
+	}
+	return Actor{ID: AnonymousActorID}
+}
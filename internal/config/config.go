@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -11,20 +13,151 @@ type Config struct {
 	Env           string `yaml:"env" env-default:"local"`
 	HTTPServer    `yaml:"http_server"`
 	DB            `yaml:"db"`
+	Webhooks      `yaml:"webhooks"`
+	Outbox        `yaml:"outbox"`
+	Auth          `yaml:"auth"`
+	RateLimit     `yaml:"rate_limit"`
+	Idempotency   `yaml:"idempotency"`
 	LogLevel      string `yaml:"log_level" env-default:"info"`
 	EnableSwagger bool   `yaml:"enable_swagger" env-default:"true"`
 }
 
+// HTTPServer - адрес сервера. TrustedProxies - адреса и подсети прокси, которым
+// можно верить в X-Forwarded-For и X-Real-IP; по умолчанию не доверяется никому
+// и IP клиента берётся из соединения.
 type HTTPServer struct {
-	Address string `yaml:"address" env-default:":8080"`
+	Address        string   `yaml:"address" env-default:":8080"`
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
+// DB - подключение к базе. Driver - postgres или sqlite; для postgres используются
+// Host, Port, Username, Password и DBName, для sqlite - путь к файлу базы Path.
+// При AutoMigrate сервис при старте применяет встроенные миграции.
 type DB struct {
-	Host     string `yaml:"host" env-default:"localhost"`
-	Port     uint   `yaml:"port" env-default:"5432"`
-	Username string `yaml:"username" env-default:"postgres"`
-	Password string `yaml:"password" env-required:"true"`
-	DBName   string `yaml:"db_name" env-required:"true"`
+	Driver      string  `yaml:"driver" env-default:"postgres"`
+	Host        string  `yaml:"host" env-default:"localhost"`
+	Port        uint    `yaml:"port" env-default:"5432"`
+	Username    string  `yaml:"username" env-default:"postgres"`
+	Password    string  `yaml:"password"`
+	DBName      string  `yaml:"db_name"`
+	Path        string  `yaml:"path" env-default:"go_rest_api.db"`
+	AutoMigrate bool    `yaml:"auto_migrate"`
+	TxRetry     TxRetry `yaml:"tx_retry"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// TxRetry - повтор транзакций, прерванных из-за конфликта с параллельной
+// транзакцией (serialization_failure и deadlock_detected в Postgres, SQLITE_BUSY
+// в SQLite). Транзакция выполняется не более MaxAttempts раз, перед попыткой
+// n+1 - пауза BaseBackoff * 2^(n-1), но не больше MaxBackoff.
+type TxRetry struct {
+	MaxAttempts int           `yaml:"max_attempts" env-default:"3"`
+	BaseBackoff time.Duration `yaml:"base_backoff" env-default:"10ms"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env-default:"200ms"`
+}
+
+type Webhooks struct {
+	GitHub   GitHubWebhook    `yaml:"github"`
+	GitLab   GitLabWebhook    `yaml:"gitlab"`
+	Outgoing OutgoingWebhooks `yaml:"outgoing"`
+}
+
+// GitHubWebhook - настройки приёма событий GitHub. Без Enabled маршрут
+// /webhooks/github не регистрируется. Users сопоставляет логин GitHub с user_id
+// сервиса (например, octocat: u1).
+type GitHubWebhook struct {
+	Enabled bool              `yaml:"enabled" env:"GITHUB_WEBHOOK_ENABLED"`
+	Secret  string            `yaml:"secret" env:"GITHUB_WEBHOOK_SECRET"`
+	Users   map[string]string `yaml:"users"`
+}
+
+// GitLabWebhook - настройки приёма событий GitLab. Без Enabled маршрут
+// /webhooks/gitlab не регистрируется. Пользователи GitLab сопоставляются через
+// таблицу gitlab_users (/webhooks/gitlab/users).
+type GitLabWebhook struct {
+	Enabled bool   `yaml:"enabled" env:"GITLAB_WEBHOOK_ENABLED"`
+	Token   string `yaml:"token" env:"GITLAB_WEBHOOK_TOKEN"`
+}
+
+// OutgoingWebhooks - параметры доставки событий подписчикам. Неудачная попытка
+// повторяется через BaseBackoff * 2^(n-1), но не больше MaxBackoff. Выбранная
+// пачка доставок закрепляется за экземпляром на ClaimLease; срок должен покрывать
+// отправку всей пачки (до BatchSize * Timeout).
+type OutgoingWebhooks struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+	MaxAttempts  int           `yaml:"max_attempts" env-default:"8"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env-default:"1s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env-default:"5m"`
+	ClaimLease   time.Duration `yaml:"claim_lease" env-default:"10m"`
+}
+
+// Outbox - параметры доставки доменных событий из таблицы outbox. Sinks - список
+// получателей: log, http (отправка на HTTP.URL) и webhooks (рассылка подписчикам).
+// Если хотя бы один получатель не принял событие, оно отправляется всем повторно
+// через BaseBackoff * 2^(n-1), но не больше MaxBackoff.
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env-default:"1s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env-default:"5m"`
+	Sinks        []string      `yaml:"sinks" env-default:"log,webhooks"`
+	HTTP         OutboxHTTP    `yaml:"http"`
+}
+
+type OutboxHTTP struct {
+	URL     string        `yaml:"url" env:"OUTBOX_HTTP_URL"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+// Auth - проверка bearer-токенов. Tokens - статические токены с ролью admin или
+// user; для user subject - это user_id. AdminToken добавляет к ним токен роли
+// admin с subject admin. Если задан JWT.Secret, принимаются также JWT с подписью
+// HS256 и claims sub и role.
+type Auth struct {
+	Tokens     []StaticToken `yaml:"tokens"`
+	AdminToken string        `yaml:"admin_token" env:"AUTH_ADMIN_TOKEN"`
+	JWT        JWTAuth       `yaml:"jwt"`
+}
+
+type StaticToken struct {
+	Token   string `yaml:"token"`
+	Subject string `yaml:"subject"`
+	Role    string `yaml:"role"`
+}
+
+type JWTAuth struct {
+	Secret string `yaml:"secret" env:"AUTH_JWT_SECRET"`
+	Issuer string `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+}
+
+// RateLimit - ограничение частоты запросов на клиента (subject токена или IP).
+// Routes задаёт отдельные лимиты для маршрутов вида "POST /pullRequest/create",
+// они не расходуют общий лимит Default. IP - лимит на все запросы с одного IP до
+// проверки токена, он ограничивает перебор токенов.
+type RateLimit struct {
+	Enabled bool                     `yaml:"enabled" env-default:"true"`
+	Default RateLimitRule            `yaml:"default"`
+	Routes  map[string]RateLimitRule `yaml:"routes"`
+	IP      RateLimitRule            `yaml:"ip"`
+}
+
+type RateLimitRule struct {
+	RPS   float64 `yaml:"rps" env-default:"50"`
+	Burst int     `yaml:"burst" env-default:"100"`
+}
+
+// Idempotency - сколько хранится ответ на запрос с Idempotency-Key (TTL) и на
+// сколько ключ занимается, пока запрос выполняется (PendingLease). PendingLease
+// должен быть больше времени обработки запроса.
+type Idempotency struct {
+	TTL          time.Duration `yaml:"ttl" env-default:"24h"`
+	PendingLease time.Duration `yaml:"pending_lease" env-default:"1m"`
 }
 
 func MustLoad() *Config {
@@ -43,5 +176,45 @@ func MustLoad() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	if cfg.Auth.AdminToken != "" {
+		cfg.Auth.Tokens = append(cfg.Auth.Tokens, StaticToken{
+			Token:   cfg.Auth.AdminToken,
+			Subject: "admin",
+			Role:    "admin",
+		})
+	}
+
 	return &cfg
 }
+
+// Validate проверяет перед запуском сервера, что вне локального окружения (env
+// local или dev) заданы секреты: хотя бы один способ получить роль admin (JWT или
+// статический токен admin) и секреты включённых вебхуков, без которых они
+// отклоняют все события. Миграциям секреты не нужны, и для них Validate не
+// вызывается.
+func (c *Config) Validate() error {
+	if c.Env == "local" || c.Env == "dev" {
+		return nil
+	}
+
+	var errs []error
+	if c.Auth.JWT.Secret == "" && !c.Auth.hasAdminToken() {
+		errs = append(errs, errors.New("no admin authentication: set auth.jwt.secret (AUTH_JWT_SECRET) or an admin token (AUTH_ADMIN_TOKEN)"))
+	}
+	if c.Webhooks.GitHub.Enabled && c.Webhooks.GitHub.Secret == "" {
+		errs = append(errs, errors.New("webhooks.github is enabled but its secret (GITHUB_WEBHOOK_SECRET) is empty"))
+	}
+	if c.Webhooks.GitLab.Enabled && c.Webhooks.GitLab.Token == "" {
+		errs = append(errs, errors.New("webhooks.gitlab is enabled but its token (GITLAB_WEBHOOK_TOKEN) is empty"))
+	}
+	return errors.Join(errs...)
+}
+
+func (a Auth) hasAdminToken() bool {
+	for _, token := range a.Tokens {
+		if token.Role == "admin" && token.Token != "" {
+			return true
+		}
+	}
+	return false
+}
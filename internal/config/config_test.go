@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_RequiresSecretsOutsideLocal(t *testing.T) {
+	for _, env := range []string{"local", "dev"} {
+		cfg := Config{Env: env}
+		cfg.Webhooks.GitHub.Enabled = true
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("env %s: err = %v, want nil", env, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(cfg *Config)
+		wantErr string
+	}{
+		{"no admin auth", func(*Config) {}, "no admin authentication"},
+		{"only user token", func(cfg *Config) {
+			cfg.Auth.Tokens = []StaticToken{{Token: "u", Subject: "u1", Role: "user"}}
+		}, "no admin authentication"},
+		{"jwt", func(cfg *Config) { cfg.Auth.JWT.Secret = "j" }, ""},
+		{"admin token", func(cfg *Config) {
+			cfg.Auth.Tokens = []StaticToken{{Token: "a", Subject: "admin", Role: "admin"}}
+		}, ""},
+		{"github enabled without secret", func(cfg *Config) {
+			cfg.Auth.JWT.Secret = "j"
+			cfg.Webhooks.GitHub.Enabled = true
+		}, "GITHUB_WEBHOOK_SECRET"},
+		{"gitlab enabled without token", func(cfg *Config) {
+			cfg.Auth.JWT.Secret = "j"
+			cfg.Webhooks.GitLab.Enabled = true
+		}, "GITLAB_WEBHOOK_TOKEN"},
+		{"webhooks enabled with secrets", func(cfg *Config) {
+			cfg.Auth.JWT.Secret = "j"
+			cfg.Webhooks.GitHub = GitHubWebhook{Enabled: true, Secret: "s"}
+			cfg.Webhooks.GitLab = GitLabWebhook{Enabled: true, Token: "t"}
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Env: "prod"}
+			tt.setup(&cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want mention of %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/migrations"
+)
+
+// NewProvider возвращает goose.Provider для встроенных миграций драйвера driver.
+// В Postgres миграции выполняются под сессионной advisory-блокировкой: реплики,
+// запущенные одновременно, применяют их по очереди, а остальные ждут и видят
+// уже обновлённую схему. С файлом SQLite работает один экземпляр сервиса.
+func NewProvider(db *sql.DB, driver string) (*goose.Provider, error) {
+	switch driver {
+	case config.DriverPostgres:
+		locker, err := lock.NewPostgresSessionLocker()
+		if err != nil {
+			return nil, err
+		}
+		return goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	case config.DriverSQLite:
+		fsys, err := fs.Sub(migrations.FS, "sqlite")
+		if err != nil {
+			return nil, err
+		}
+		return goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	default:
+		return nil, fmt.Errorf("unknown db driver: %s", driver)
+	}
+}
@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/pressly/goose/v3"
+
+	"go-rest-api/internal/config"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countApplied(t *testing.T, provider *goose.Provider) int {
+	t.Helper()
+
+	statuses, err := provider.Status(context.Background())
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	applied := 0
+	for _, status := range statuses {
+		if status.State == goose.StateApplied {
+			applied++
+		}
+	}
+	return applied
+}
+
+func TestSQLiteUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	provider, err := NewProvider(db, config.DriverSQLite)
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	total := len(provider.ListSources())
+
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if got := countApplied(t, provider); got != total {
+		t.Fatalf("applied %d of %d migrations", got, total)
+	}
+	if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (1, 'alice')`); err != nil {
+		t.Fatalf("schema is not usable: %v", err)
+	}
+
+	if _, err := provider.Down(ctx); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if got := countApplied(t, provider); got != total-1 {
+		t.Fatalf("after down applied %d, want %d", got, total-1)
+	}
+}
+
+// Версии миграций Postgres идут подряд с 1: пропуск или дубль номера обычно
+// означает, что миграция добавлена не в тот каталог.
+func TestPostgresSourcesAreSequential(t *testing.T) {
+	provider, err := NewProvider(openSQLite(t), config.DriverPostgres)
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	sources := provider.ListSources()
+	if len(sources) == 0 {
+		t.Fatal("no postgres migrations embedded")
+	}
+	for i, source := range sources {
+		if source.Version != int64(i+1) {
+			t.Fatalf("migration %s has version %d, want %d", filepath.Base(source.Path), source.Version, i+1)
+		}
+	}
+}
+
+func TestUnknownDriver(t *testing.T) {
+	if _, err := NewProvider(openSQLite(t), "mysql"); err == nil {
+		t.Fatal("expected error for unknown driver")
+	}
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditEntityType - вид сущности, которую изменила операция.
+type AuditEntityType string
+
+const (
+	AuditEntityPullRequest AuditEntityType = "pull_request"
+	AuditEntityTeam        AuditEntityType = "team"
+	AuditEntityUser        AuditEntityType = "user"
+)
+
+// TargetIDs хранится JSON-массивом строк.
+type TargetIDs []string
+
+func (t TargetIDs) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(t))
+	return string(data), err
+}
+
+func (t *TargetIDs) Scan(value any) error {
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	case nil:
+		*t = TargetIDs{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TargetIDs", value)
+	}
+	return json.Unmarshal(raw, (*[]string)(t))
+}
+
+// AuditEntry - запись журнала изменений. EntityID - основная сущность операции,
+// TargetIDs - остальные затронутые (участники команды, ревьюверы, PR). Diff хранит
+// JSON-объект {поле: {before, after}} только с изменившимися полями.
+type AuditEntry struct {
+	ID         uint64          `gorm:"primaryKey"`
+	OccurredAt time.Time       `gorm:"not null"`
+	Actor      string          `gorm:"size:255;not null"`
+	Operation  string          `gorm:"size:64;not null"`
+	EntityType AuditEntityType `gorm:"size:32;not null"`
+	EntityID   string          `gorm:"size:255;not null"`
+	TargetIDs  TargetIDs       `gorm:"type:jsonb;not null"`
+	Diff       string          `gorm:"type:jsonb;not null"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,23 @@
+package model
+
+type ExternalProvider string
+
+const (
+	ExternalProviderGitHub ExternalProvider = "github"
+	ExternalProviderGitLab ExternalProvider = "gitlab"
+)
+
+// ExternalPullRequest связывает PR во внешней системе с PR сервиса. Номера PR
+// уникальны только в пределах репозитория, поэтому ключ - тройка
+// (provider, repository, number): для GitHub repository - full_name, для
+// GitLab - id проекта, number - номер PR или iid MR.
+type ExternalPullRequest struct {
+	Provider   ExternalProvider `gorm:"primaryKey;size:16"`
+	Repository string           `gorm:"primaryKey;size:255"`
+	Number     uint64           `gorm:"primaryKey"`
+	PrID       uint             `gorm:"not null;uniqueIndex"`
+}
+
+func (ExternalPullRequest) TableName() string {
+	return "external_pull_requests"
+}
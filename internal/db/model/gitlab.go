@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// GitLabUser сопоставляет username в GitLab с пользователем сервиса.
+type GitLabUser struct {
+	Username string `gorm:"primaryKey;size:255"`
+	UserID   uint   `gorm:"not null"`
+
+	User User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (GitLabUser) TableName() string {
+	return "gitlab_users"
+}
+
+// GitLabWebhookEvent - уже обработанное событие GitLab, по X-Gitlab-Event-UUID.
+type GitLabWebhookEvent struct {
+	EventUUID  string    `gorm:"primaryKey;size:64"`
+	ReceivedAt time.Time `gorm:"not null"`
+}
+
+func (GitLabWebhookEvent) TableName() string {
+	return "gitlab_webhook_events"
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// IdempotencyKey - запрос с заголовком Idempotency-Key и его ответ. Пока запрос
+// выполняется, StatusCode пустой. Scope отделяет ключи разных клиентов и маршрутов.
+// LeaseToken выдаётся при резервировании: сохранить ответ или освободить ключ
+// может только тот, кто его зарезервировал.
+type IdempotencyKey struct {
+	Scope        string `gorm:"primaryKey;size:512"`
+	Key          string `gorm:"primaryKey;column:idempotency_key;size:255"`
+	RequestHash  string `gorm:"size:64;not null"`
+	LeaseToken   string `gorm:"size:64;not null"`
+	StatusCode   *int
+	ContentType  string `gorm:"size:255;not null"`
+	ResponseBody []byte
+	CreatedAt    time.Time `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != nil
+}
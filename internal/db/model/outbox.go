@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// OutboxMessage - доменное событие, записанное в той же транзакции, что и
+// изменение, которое его породило. Payload хранит только data события.
+type OutboxMessage struct {
+	ID            uint64    `gorm:"primaryKey"`
+	EventType     string    `gorm:"size:64;not null"`
+	Payload       string    `gorm:"type:jsonb;not null"`
+	OccurredAt    time.Time `gorm:"not null"`
+	Attempts      int       `gorm:"not null"`
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string    `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+	DeliveredAt   *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
@@ -1,10 +1,21 @@
 package model
 
+import "time"
+
 type PrStatus string
 
 const (
 	PrStatusOpen   PrStatus = "OPEN"
 	PrStatusMerged PrStatus = "MERGED"
+	PrStatusClosed PrStatus = "CLOSED"
+)
+
+type ReviewState string
+
+const (
+	ReviewStatePending          ReviewState = "PENDING"
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
 )
 
 type PullRequest struct {
@@ -12,9 +23,18 @@ type PullRequest struct {
 	Title    string `gorm:"size:255;not null"`
 	AuthorID uint   `gorm:"not null"`
 
-	Author    User     `gorm:"foreignKey:AuthorID;constraint:OnDelete:RESTRICT"`
-	Status    PrStatus `gorm:"type:pr_status;default:OPEN;not null"`
-	Reviewers []User   `gorm:"many2many:pull_request_reviewer;foreignKey:ID;joinForeignKey:PrID;References:ID;joinReferences:ReviewerID"`
+	Author    User                  `gorm:"foreignKey:AuthorID;constraint:OnDelete:RESTRICT"`
+	TeamID    *uint                 `gorm:"index:idx_pull_requests_team_id"`
+	Team      *Team                 `gorm:"foreignKey:TeamID;constraint:OnDelete:SET NULL"`
+	Status    PrStatus              `gorm:"size:16;default:OPEN;not null"`
+	Reviewers []User                `gorm:"many2many:pull_request_reviewer;foreignKey:ID;joinForeignKey:PrID;References:ID;joinReferences:ReviewerID"`
+	Reviews   []PullRequestReviewer `gorm:"foreignKey:PrID"`
+
+	CreatedAt time.Time `gorm:"not null;index:idx_pull_requests_created_at"`
+	// UpdatedAt выставляет сервис тем же временем, что и MergedAt, поэтому
+	// GORM не переписывает его при Save.
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime:false"`
+	MergedAt  *time.Time
 }
 
 func (PullRequest) TableName() string {
@@ -25,6 +45,9 @@ type PullRequestReviewer struct {
 	PrID       uint `gorm:"primaryKey"`
 	ReviewerID uint `gorm:"primaryKey;index:idx_reviewer_id"`
 
+	State      ReviewState `gorm:"size:32;default:PENDING;not null"`
+	ReviewedAt *time.Time
+
 	PullRequest PullRequest `gorm:"foreignKey:PrID;constraint:OnDelete:CASCADE"`
 	Reviewer    User        `gorm:"constraint:OnDelete:RESTRICT"`
 }
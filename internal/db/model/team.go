@@ -1,7 +1,22 @@
 package model
 
 type Team struct {
-	ID      uint   `gorm:"primaryKey"`
-	Name    string `gorm:"size:255;not null"`
-	Members []User `gorm:"many2many:user_team"`
+	ID       uint          `gorm:"primaryKey"`
+	Name     string        `gorm:"size:255;not null"`
+	Members  []User        `gorm:"many2many:user_team"`
+	Settings *TeamSettings `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE"`
+}
+
+type TeamSettings struct {
+	TeamID           uint   `gorm:"primaryKey"`
+	MinReviewers     int    `gorm:"not null"`
+	MaxReviewers     int    `gorm:"not null"`
+	ReviewerStrategy string `gorm:"size:32;not null"`
+
+	// RequiredApprovals - сколько одобрений нужно для merge (0 - без проверки)
+	RequiredApprovals int `gorm:"not null"`
+}
+
+func (TeamSettings) TableName() string {
+	return "team_settings"
 }
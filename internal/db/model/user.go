@@ -1,11 +1,15 @@
 package model
 
+import "time"
+
 type User struct {
-	ID       uint   `gorm:"primaryKey"`
-	Name     string `gorm:"size:255;not null"`
-	IsActive bool   `gorm:"not null;default:true"`
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:255;not null"`
+	// Без default в теге: gorm заменил бы false на значение по умолчанию при Create.
+	IsActive bool `gorm:"not null"`
 
 	Teams                []Team        `gorm:"many2many:user_team"`
+	Memberships          []UserTeam    `gorm:"foreignKey:UserID"`
 	AuthoredPullRequests []PullRequest `gorm:"foreignKey:AuthorID"`
 	ReviewedPullRequests []PullRequest `gorm:"many2many:pull_request_reviewer;foreignKey:ID;joinForeignKey:ReviewerID;References:ID;joinReferences:PrID"`
 }
@@ -13,6 +17,9 @@ type User struct {
 type UserTeam struct {
 	UserID uint `gorm:"primaryKey"`
 	TeamID uint `gorm:"primaryKey"`
+	// IsPrimary - основная команда пользователя, из неё выбираются ревьюверы,
+	// если команда не указана явно
+	IsPrimary bool `gorm:"not null;default:false"`
 
 	User User
 	Team Team
@@ -21,3 +28,26 @@ type UserTeam struct {
 func (UserTeam) TableName() string {
 	return "user_team"
 }
+
+// PrimaryTeam возвращает основную команду пользователя. Memberships должны быть
+// загружены, как это делает UserRepository.GetByIDWithTeams.
+func (u *User) PrimaryTeam() *Team {
+	if len(u.Memberships) == 0 {
+		return nil
+	}
+	return &u.Memberships[0].Team
+}
+
+type UserUnavailability struct {
+	ID       uint      `gorm:"primaryKey"`
+	UserID   uint      `gorm:"not null;index:idx_user_unavailability_user_id"`
+	StartsAt time.Time `gorm:"not null"`
+	EndsAt   time.Time `gorm:"not null"`
+	Reason   string    `gorm:"size:255;not null"`
+
+	User User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (UserUnavailability) TableName() string {
+	return "user_unavailability"
+}
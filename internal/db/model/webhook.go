@@ -0,0 +1,85 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventTypes хранится в одной текстовой колонке через запятую.
+type EventTypes []string
+
+func (t EventTypes) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *EventTypes) Scan(value any) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+		*t = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into EventTypes", value)
+	}
+
+	if raw == "" {
+		*t = EventTypes{}
+		return nil
+	}
+	*t = strings.Split(raw, ",")
+	return nil
+}
+
+func (t EventTypes) Contains(eventType string) bool {
+	for _, et := range t {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookSubscription struct {
+	ID         uint       `gorm:"primaryKey"`
+	URL        string     `gorm:"not null"`
+	Secret     string     `gorm:"not null"`
+	EventTypes EventTypes `gorm:"type:text;not null"`
+	CreatedAt  time.Time  `gorm:"not null"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+type WebhookDelivery struct {
+	ID             uint64                `gorm:"primaryKey"`
+	SubscriptionID uint                  `gorm:"not null"`
+	EventType      string                `gorm:"size:64;not null"`
+	Payload        string                `gorm:"type:jsonb;not null"`
+	Status         WebhookDeliveryStatus `gorm:"size:16;default:PENDING;not null"`
+	Attempts       int                   `gorm:"not null"`
+	NextAttemptAt  time.Time             `gorm:"not null"`
+	LastError      string                `gorm:"not null"`
+	CreatedAt      time.Time             `gorm:"not null"`
+	DeliveredAt    *time.Time
+
+	Subscription WebhookSubscription `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+)
+
+type AuditFilter struct {
+	EntityType model.AuditEntityType
+	// EntityID совпадает как с основной сущностью записи, так и с любой из затронутых.
+	EntityID string
+	Actor    string
+	From     *time.Time
+	To       *time.Time
+}
+
+// AuditQuery возвращает записи от новых к старым. BeforeID - id последней записи
+// предыдущей страницы.
+type AuditQuery struct {
+	Filter   AuditFilter
+	BeforeID uint64
+	Limit    int
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditEntry) error
+	List(ctx context.Context, q AuditQuery) ([]model.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *auditRepository) List(ctx context.Context, q AuditQuery) ([]model.AuditEntry, error) {
+	db := r.db.WithContext(ctx)
+
+	f := q.Filter
+	if f.EntityType != "" {
+		db = db.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		db = db.Where("(entity_id = ? OR "+targetIDsContain(r.db)+")", f.EntityID, f.EntityID)
+	}
+	if f.Actor != "" {
+		db = db.Where("actor = ?", f.Actor)
+	}
+	if f.From != nil {
+		db = db.Where("occurred_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("occurred_at < ?", *f.To)
+	}
+	if q.BeforeID > 0 {
+		db = db.Where("id < ?", q.BeforeID)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+
+	var entries []model.AuditEntry
+	err := db.Order("id DESC").Find(&entries).Error
+	return entries, err
+}
+
+// targetIDsContain - условие "target_ids содержит ?" на диалекте базы db.
+func targetIDsContain(db *gorm.DB) string {
+	if isSQLite(db) {
+		return "EXISTS (SELECT 1 FROM json_each(audit_log.target_ids) WHERE json_each.value = ?)"
+	}
+	return "target_ids @> jsonb_build_array(?::text)"
+}
+
+func (r *auditRepository) WithTx(tx *gorm.DB) *auditRepository {
+	return &auditRepository{
+		db: tx,
+	}
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "gorm.io/gorm"
+
+// isSQLite сообщает, что db подключена к SQLite, а не к Postgres. Нужна там,
+// где запрос или блокировки не переносятся между базами.
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+)
+
+type ExternalPullRequestRepository interface {
+	Get(ctx context.Context, provider model.ExternalProvider, repository string, number uint64) (*model.ExternalPullRequest, error)
+	Create(ctx context.Context, link *model.ExternalPullRequest) error
+}
+
+type externalPullRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalPullRequestRepository(db *gorm.DB) ExternalPullRequestRepository {
+	return &externalPullRequestRepository{
+		db: db,
+	}
+}
+
+func (r *externalPullRequestRepository) Get(
+	ctx context.Context,
+	provider model.ExternalProvider,
+	repository string,
+	number uint64,
+) (*model.ExternalPullRequest, error) {
+	var link model.ExternalPullRequest
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND repository = ? AND number = ?", provider, repository, number).
+		First(&link).Error
+	return &link, err
+}
+
+func (r *externalPullRequestRepository) Create(ctx context.Context, link *model.ExternalPullRequest) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
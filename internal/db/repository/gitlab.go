@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-rest-api/internal/db/model"
+)
+
+type GitLabRepository interface {
+	GetUser(ctx context.Context, username string) (*model.GitLabUser, error)
+	ListUsers(ctx context.Context) ([]model.GitLabUser, error)
+	SaveUser(ctx context.Context, user *model.GitLabUser) error
+	DeleteUser(ctx context.Context, username string) (bool, error)
+
+	RecordEvent(ctx context.Context, eventUUID string, receivedAt time.Time) error
+}
+
+type gitlabRepository struct {
+	db *gorm.DB
+}
+
+func NewGitLabRepository(db *gorm.DB) GitLabRepository {
+	return &gitlabRepository{
+		db: db,
+	}
+}
+
+func (r *gitlabRepository) GetUser(ctx context.Context, username string) (*model.GitLabUser, error) {
+	var user model.GitLabUser
+	err := r.db.WithContext(ctx).
+		Where("username = ?", username).
+		First(&user).Error
+	return &user, err
+}
+
+func (r *gitlabRepository) ListUsers(ctx context.Context) ([]model.GitLabUser, error) {
+	var users []model.GitLabUser
+	err := r.db.WithContext(ctx).
+		Order("username").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *gitlabRepository) SaveUser(ctx context.Context, user *model.GitLabUser) error {
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "username"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id"}),
+		}).
+		Create(user).Error
+}
+
+func (r *gitlabRepository) DeleteUser(ctx context.Context, username string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("username = ?", username).
+		Delete(&model.GitLabUser{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordEvent отмечает событие обработанным. Для уже записанного события
+// возвращает gorm.ErrDuplicatedKey.
+func (r *gitlabRepository) RecordEvent(ctx context.Context, eventUUID string, receivedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Create(&model.GitLabWebhookEvent{EventUUID: eventUUID, ReceivedAt: receivedAt}).Error
+}
+
+func (r *gitlabRepository) WithTx(tx *gorm.DB) *gitlabRepository {
+	return &gitlabRepository{
+		db: tx,
+	}
+}
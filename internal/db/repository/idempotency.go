@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-rest-api/internal/db/model"
+)
+
+type IdempotencyRepository interface {
+	// Reserve сохраняет новый ключ. Если ключ уже есть и не истёк, ничего не меняет
+	// и возвращает существующую запись. Истёкший ключ, в том числе незавершённый,
+	// занимается заново.
+	Reserve(ctx context.Context, rec *model.IdempotencyKey) (*model.IdempotencyKey, error)
+	// Complete сохраняет ответ из rec (StatusCode, ContentType, ResponseBody) и
+	// продлевает ключ до rec.ExpiresAt. Ключ должен быть незавершённым и
+	// зарезервированным этим же запросом (RequestHash и LeaseToken), иначе
+	// возвращается gorm.ErrRecordNotFound.
+	Complete(ctx context.Context, rec *model.IdempotencyKey) error
+	// Release удаляет незавершённый ключ, зарезервированный с leaseToken, чтобы
+	// запрос можно было повторить.
+	Release(ctx context.Context, scope, key, leaseToken string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{
+		db: db,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, rec *model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	var existing *model.IdempotencyKey
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("scope = ? AND idempotency_key = ? AND expires_at <= ?", rec.Scope, rec.Key, rec.CreatedAt).
+			Delete(&model.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+
+		existing = &model.IdempotencyKey{}
+		return tx.
+			Where("scope = ? AND idempotency_key = ?", rec.Scope, rec.Key).
+			First(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, rec *model.IdempotencyKey) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.IdempotencyKey{}).
+		Where("scope = ? AND idempotency_key = ? AND status_code IS NULL AND request_hash = ? AND lease_token = ?",
+			rec.Scope, rec.Key, rec.RequestHash, rec.LeaseToken).
+		Updates(map[string]any{
+			"status_code":   rec.StatusCode,
+			"content_type":  rec.ContentType,
+			"response_body": rec.ResponseBody,
+			"expires_at":    rec.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, scope, key, leaseToken string) error {
+	return r.db.WithContext(ctx).
+		Where("scope = ? AND idempotency_key = ? AND lease_token = ? AND status_code IS NULL", scope, key, leaseToken).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type auditRepository struct {
+	conn *conn
+}
+
+var _ repository.AuditRepository = (*auditRepository)(nil)
+
+func (r *auditRepository) Create(_ context.Context, entry *model.AuditEntry) error {
+	return r.conn.write(func(d *state) error {
+		d.nextAuditID++
+		entry.ID = d.nextAuditID
+		row := *entry
+		row.TargetIDs = slices.Clone(entry.TargetIDs)
+		d.audit[entry.ID] = row
+		return nil
+	})
+}
+
+func (r *auditRepository) List(_ context.Context, q repository.AuditQuery) ([]model.AuditEntry, error) {
+	var entries []model.AuditEntry
+	err := r.conn.read(func(d *state) error {
+		rows := sortedValues(d.audit)
+		slices.Reverse(rows)
+
+		f := q.Filter
+		for _, entry := range rows {
+			switch {
+			case f.EntityType != "" && entry.EntityType != f.EntityType,
+				f.EntityID != "" && entry.EntityID != f.EntityID && !slices.Contains(entry.TargetIDs, f.EntityID),
+				f.Actor != "" && entry.Actor != f.Actor,
+				f.From != nil && entry.OccurredAt.Before(*f.From),
+				f.To != nil && !entry.OccurredAt.Before(*f.To),
+				q.BeforeID > 0 && entry.ID >= q.BeforeID:
+				continue
+			}
+			entries = append(entries, entry)
+			if q.Limit > 0 && len(entries) == q.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// AuditEntries возвращает все записи журнала в порядке записи.
+func (s *Store) AuditEntries() []model.AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.data.audit)
+}
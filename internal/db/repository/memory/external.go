@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type externalKey struct {
+	Provider   model.ExternalProvider
+	Repository string
+	Number     uint64
+}
+
+type externalPullRequestRepository struct {
+	conn *conn
+}
+
+var _ repository.ExternalPullRequestRepository = (*externalPullRequestRepository)(nil)
+
+func (r *externalPullRequestRepository) Get(
+	_ context.Context,
+	provider model.ExternalProvider,
+	repository string,
+	number uint64,
+) (*model.ExternalPullRequest, error) {
+	var link model.ExternalPullRequest
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.externalPullRequests[externalKey{Provider: provider, Repository: repository, Number: number}]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		link = row
+		return nil
+	})
+	return &link, err
+}
+
+func (r *externalPullRequestRepository) Create(_ context.Context, link *model.ExternalPullRequest) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.pullRequests[link.PrID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		key := externalKey{Provider: link.Provider, Repository: link.Repository, Number: link.Number}
+		if _, ok := d.externalPullRequests[key]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		for _, row := range d.externalPullRequests {
+			if row.PrID == link.PrID {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+		d.externalPullRequests[key] = *link
+		return nil
+	})
+}
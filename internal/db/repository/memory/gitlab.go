@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type gitlabRepository struct {
+	conn *conn
+}
+
+var _ repository.GitLabRepository = (*gitlabRepository)(nil)
+
+func (r *gitlabRepository) GetUser(_ context.Context, username string) (*model.GitLabUser, error) {
+	var user model.GitLabUser
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.gitlabUsers[username]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		user = row
+		return nil
+	})
+	return &user, err
+}
+
+func (r *gitlabRepository) ListUsers(_ context.Context) ([]model.GitLabUser, error) {
+	var users []model.GitLabUser
+	err := r.conn.read(func(d *state) error {
+		users = sortedValues(d.gitlabUsers)
+		return nil
+	})
+	return users, err
+}
+
+func (r *gitlabRepository) SaveUser(_ context.Context, user *model.GitLabUser) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.users[user.UserID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		d.gitlabUsers[user.Username] = model.GitLabUser{Username: user.Username, UserID: user.UserID}
+		return nil
+	})
+}
+
+func (r *gitlabRepository) DeleteUser(_ context.Context, username string) (bool, error) {
+	var deleted bool
+	err := r.conn.write(func(d *state) error {
+		_, deleted = d.gitlabUsers[username]
+		delete(d.gitlabUsers, username)
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *gitlabRepository) RecordEvent(_ context.Context, eventUUID string, receivedAt time.Time) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.gitlabEvents[eventUUID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		d.gitlabEvents[eventUUID] = model.GitLabWebhookEvent{EventUUID: eventUUID, ReceivedAt: receivedAt}
+		return nil
+	})
+}
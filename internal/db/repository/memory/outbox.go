@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
+)
+
+type outboxRepository struct {
+	conn *conn
+}
+
+var _ repository.OutboxRepository = (*outboxRepository)(nil)
+
+func (r *outboxRepository) Enqueue(_ context.Context, evs ...events.Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	return r.conn.write(func(d *state) error {
+		for _, ev := range evs {
+			payload, err := json.Marshal(ev.Data)
+			if err != nil {
+				return err
+			}
+			d.nextOutboxID++
+			d.outbox[d.nextOutboxID] = model.OutboxMessage{
+				ID:            d.nextOutboxID,
+				EventType:     string(ev.Type),
+				Payload:       string(payload),
+				OccurredAt:    ev.OccurredAt,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			}
+		}
+		return nil
+	})
+}
+
+func (r *outboxRepository) ProcessPending(
+	_ context.Context,
+	now time.Time,
+	limit int,
+	handle func(msgs []model.OutboxMessage) error,
+) error {
+	return r.conn.write(func(d *state) error {
+		var msgs []model.OutboxMessage
+		for _, msg := range d.outbox {
+			if msg.DeliveredAt == nil && !msg.NextAttemptAt.After(now) {
+				msgs = append(msgs, msg)
+			}
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+
+		slices.SortFunc(msgs, func(a, b model.OutboxMessage) int {
+			return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+		})
+		if limit > 0 && len(msgs) > limit {
+			msgs = msgs[:limit]
+		}
+
+		if err := handle(msgs); err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			d.outbox[msg.ID] = msg
+		}
+		return nil
+	})
+}
+
+// OutboxMessages возвращает все сообщения outbox в порядке записи.
+func (s *Store) OutboxMessages() []model.OutboxMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.data.outbox)
+}
@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type pullRequestRepository struct {
+	conn *conn
+}
+
+var _ repository.PullRequestRepository = (*pullRequestRepository)(nil)
+
+// pullRequestRow - строка pull_requests без связей.
+func pullRequestRow(pr model.PullRequest) model.PullRequest {
+	return model.PullRequest{
+		ID:        pr.ID,
+		Title:     pr.Title,
+		AuthorID:  pr.AuthorID,
+		TeamID:    pr.TeamID,
+		Status:    pr.Status,
+		CreatedAt: pr.CreatedAt,
+		UpdatedAt: pr.UpdatedAt,
+		MergedAt:  pr.MergedAt,
+	}
+}
+
+func (r *pullRequestRepository) Create(_ context.Context, pr *model.PullRequest) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.users[pr.AuthorID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		if pr.ID == 0 {
+			d.nextPullRequestID++
+			pr.ID = d.nextPullRequestID
+		} else if _, ok := d.pullRequests[pr.ID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		d.nextPullRequestID = max(d.nextPullRequestID, pr.ID)
+
+		if pr.Status == "" {
+			pr.Status = model.PrStatusOpen
+		}
+		now := time.Now()
+		if pr.CreatedAt.IsZero() {
+			pr.CreatedAt = now
+		}
+		if pr.UpdatedAt.IsZero() {
+			pr.UpdatedAt = now
+		}
+		d.pullRequests[pr.ID] = pullRequestRow(*pr)
+		return nil
+	})
+}
+
+func (r *pullRequestRepository) GetByID(_ context.Context, id uint) (*model.PullRequest, error) {
+	var pr model.PullRequest
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.pullRequests[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		pr = row
+		return nil
+	})
+	return &pr, err
+}
+
+func (r *pullRequestRepository) GetByIDWithRelations(_ context.Context, id uint) (*model.PullRequest, error) {
+	var pr model.PullRequest
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.pullRequests[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		pr = withRelations(d, row)
+		pr.Author = d.users[row.AuthorID]
+		return nil
+	})
+	return &pr, err
+}
+
+// GetByIDForUpdate не отличается от GetByIDWithRelations: транзакции Store
+// и так выполняются по одной.
+func (r *pullRequestRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.PullRequest, error) {
+	return r.GetByIDWithRelations(ctx, id)
+}
+
+func (r *pullRequestRepository) Update(_ context.Context, pr *model.PullRequest) error {
+	return r.conn.write(func(d *state) error {
+		d.pullRequests[pr.ID] = pullRequestRow(*pr)
+		d.nextPullRequestID = max(d.nextPullRequestID, pr.ID)
+		return nil
+	})
+}
+
+func (r *pullRequestRepository) Select(_ context.Context) ([]model.PullRequest, error) {
+	var prs []model.PullRequest
+	err := r.conn.read(func(d *state) error {
+		prs = sortedValues(d.pullRequests)
+		return nil
+	})
+	return prs, err
+}
+
+func (r *pullRequestRepository) Delete(_ context.Context, id uint) error {
+	return r.conn.write(func(d *state) error {
+		delete(d.pullRequests, id)
+		for key := range d.reviews {
+			if key.PrID == id {
+				delete(d.reviews, key)
+			}
+		}
+		for key, link := range d.externalPullRequests {
+			if link.PrID == id {
+				delete(d.externalPullRequests, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *pullRequestRepository) ExistsByID(_ context.Context, id uint) (bool, error) {
+	var exists bool
+	err := r.conn.read(func(d *state) error {
+		_, exists = d.pullRequests[id]
+		return nil
+	})
+	return exists, err
+}
+
+func (r *pullRequestRepository) GetReviewerPRs(_ context.Context, reviewerID uint) ([]model.PullRequest, error) {
+	var prs []model.PullRequest
+	err := r.conn.read(func(d *state) error {
+		for _, pr := range sortedValues(d.pullRequests) {
+			if _, ok := d.reviews[reviewKey{PrID: pr.ID, ReviewerID: reviewerID}]; ok {
+				pr.Author = d.users[pr.AuthorID]
+				prs = append(prs, pr)
+			}
+		}
+		return nil
+	})
+	return prs, err
+}
+
+func (r *pullRequestRepository) List(_ context.Context, q repository.PullRequestQuery) ([]model.PullRequest, error) {
+	var prs []model.PullRequest
+	err := r.conn.read(func(d *state) error {
+		for _, pr := range d.pullRequests {
+			if matchesFilter(d, pr, q.Filter) && afterCursor(pr, q) {
+				prs = append(prs, pr)
+			}
+		}
+
+		slices.SortFunc(prs, func(a, b model.PullRequest) int {
+			c := cmp.Compare(a.ID, b.ID)
+			if q.SortBy == repository.PullRequestSortByCreatedAt {
+				c = cmp.Or(a.CreatedAt.Compare(b.CreatedAt), c)
+			}
+			if q.Desc {
+				return -c
+			}
+			return c
+		})
+		if q.Limit > 0 && len(prs) > q.Limit {
+			prs = prs[:q.Limit]
+		}
+
+		for i := range prs {
+			prs[i] = withRelations(d, prs[i])
+		}
+		return nil
+	})
+	return prs, err
+}
+
+func (r *pullRequestRepository) AddReviewer(_ context.Context, prID, reviewerID uint) error {
+	return r.conn.write(func(d *state) error {
+		return addReviewer(d, prID, reviewerID)
+	})
+}
+
+func (r *pullRequestRepository) RemoveReviewer(_ context.Context, prID, reviewerID uint) error {
+	return r.conn.write(func(d *state) error {
+		delete(d.reviews, reviewKey{PrID: prID, ReviewerID: reviewerID})
+		return nil
+	})
+}
+
+func (r *pullRequestRepository) IsReviewerAssigned(_ context.Context, prID, reviewerID uint) (bool, error) {
+	var assigned bool
+	err := r.conn.read(func(d *state) error {
+		_, assigned = d.reviews[reviewKey{PrID: prID, ReviewerID: reviewerID}]
+		return nil
+	})
+	return assigned, err
+}
+
+func (r *pullRequestRepository) SetReviewState(_ context.Context, prID, reviewerID uint, reviewState model.ReviewState, reviewedAt time.Time) error {
+	return r.conn.write(func(d *state) error {
+		key := reviewKey{PrID: prID, ReviewerID: reviewerID}
+		review, ok := d.reviews[key]
+		if !ok {
+			return nil
+		}
+		review.State = reviewState
+		review.ReviewedAt = &reviewedAt
+		d.reviews[key] = review
+		return nil
+	})
+}
+
+func (r *pullRequestRepository) CountOpenReviews(_ context.Context, reviewerIDs []uint) (map[uint]int64, error) {
+	counts := map[uint]int64{}
+	err := r.conn.read(func(d *state) error {
+		for key := range d.reviews {
+			if slices.Contains(reviewerIDs, key.ReviewerID) && d.pullRequests[key.PrID].Status == model.PrStatusOpen {
+				counts[key.ReviewerID]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ListOpenAssignments возвращает все назначения на OPEN PR, где ревьювером
+// указан хотя бы один из reviewerIDs, включая остальных ревьюверов этих PR.
+func (r *pullRequestRepository) ListOpenAssignments(_ context.Context, reviewerIDs []uint) ([]repository.ReviewAssignment, error) {
+	var assignments []repository.ReviewAssignment
+	err := r.conn.read(func(d *state) error {
+		affected := map[uint]bool{}
+		for key := range d.reviews {
+			if slices.Contains(reviewerIDs, key.ReviewerID) {
+				affected[key.PrID] = true
+			}
+		}
+
+		for _, review := range sortedReviews(d) {
+			pr := d.pullRequests[review.PrID]
+			if !affected[review.PrID] || pr.Status != model.PrStatusOpen {
+				continue
+			}
+			assignments = append(assignments, repository.ReviewAssignment{
+				PrID:       review.PrID,
+				ReviewerID: review.ReviewerID,
+				AuthorID:   pr.AuthorID,
+				TeamID:     pr.TeamID,
+			})
+		}
+		return nil
+	})
+	return assignments, err
+}
+
+func (r *pullRequestRepository) ReplaceReviewers(_ context.Context, moves []repository.ReviewerMove) error {
+	return r.conn.write(func(d *state) error {
+		for _, move := range moves {
+			delete(d.reviews, reviewKey{PrID: move.PrID, ReviewerID: move.OldReviewerID})
+		}
+		for _, move := range moves {
+			if err := addReviewer(d, move.PrID, move.NewReviewerID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func addReviewer(d *state, prID, reviewerID uint) error {
+	if _, ok := d.pullRequests[prID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if _, ok := d.users[reviewerID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+
+	key := reviewKey{PrID: prID, ReviewerID: reviewerID}
+	if _, ok := d.reviews[key]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	d.reviews[key] = model.PullRequestReviewer{
+		PrID:       prID,
+		ReviewerID: reviewerID,
+		State:      model.ReviewStatePending,
+	}
+	return nil
+}
+
+// withRelations дополняет строку PR командой, ревьюверами и их ревью
+// (по возрастанию id ревьювера). Author загружается отдельно, как в gorm-репозитории.
+func withRelations(d *state, pr model.PullRequest) model.PullRequest {
+	if pr.TeamID != nil {
+		if team, ok := d.teams[*pr.TeamID]; ok {
+			pr.Team = &team
+		}
+	}
+
+	pr.Reviewers = []model.User{}
+	pr.Reviews = []model.PullRequestReviewer{}
+	for _, review := range sortedReviews(d) {
+		if review.PrID != pr.ID {
+			continue
+		}
+		pr.Reviewers = append(pr.Reviewers, d.users[review.ReviewerID])
+		pr.Reviews = append(pr.Reviews, review)
+	}
+	return pr
+}
+
+// sortedReviews возвращает pull_request_reviewer в порядке (pr_id, reviewer_id).
+func sortedReviews(d *state) []model.PullRequestReviewer {
+	reviews := make([]model.PullRequestReviewer, 0, len(d.reviews))
+	for _, review := range d.reviews {
+		reviews = append(reviews, review)
+	}
+	slices.SortFunc(reviews, func(a, b model.PullRequestReviewer) int {
+		return cmp.Or(cmp.Compare(a.PrID, b.PrID), cmp.Compare(a.ReviewerID, b.ReviewerID))
+	})
+	return reviews
+}
+
+func matchesFilter(d *state, pr model.PullRequest, f repository.PullRequestFilter) bool {
+	if f.Status != nil && pr.Status != *f.Status {
+		return false
+	}
+	if f.AuthorID != nil && pr.AuthorID != *f.AuthorID {
+		return false
+	}
+	if f.ReviewerID != nil {
+		if _, ok := d.reviews[reviewKey{PrID: pr.ID, ReviewerID: *f.ReviewerID}]; !ok {
+			return false
+		}
+	}
+	if f.TeamName != "" {
+		team := findTeamByName(d, f.TeamName)
+		if team == nil || pr.TeamID == nil || *pr.TeamID != team.ID {
+			return false
+		}
+	}
+	if f.CreatedFrom != nil && pr.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && !pr.CreatedAt.Before(*f.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// afterCursor сообщает, что PR идёт после курсора в порядке сортировки запроса.
+func afterCursor(pr model.PullRequest, q repository.PullRequestQuery) bool {
+	if q.After == nil {
+		return true
+	}
+
+	c := cmp.Compare(pr.ID, q.After.ID)
+	if q.SortBy == repository.PullRequestSortByCreatedAt {
+		c = cmp.Or(pr.CreatedAt.Compare(q.After.CreatedAt), c)
+	}
+	if q.Desc {
+		return c < 0
+	}
+	return c > 0
+}
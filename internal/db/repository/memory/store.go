@@ -0,0 +1,167 @@
+// Package memory - реализации репозиториев в памяти для unit-тестов сервисов.
+// Store хранит данные всех репозиториев и работает как db.UnitOfWork: транзакции
+// выполняются по одной над копией данных и либо целиком применяются, либо
+// отбрасываются.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+)
+
+type reviewKey struct {
+	PrID       uint
+	ReviewerID uint
+}
+
+type membershipKey struct {
+	UserID uint
+	TeamID uint
+}
+
+// state - содержимое "таблиц". Связи (Teams, Members, Reviewers...) в строках
+// не хранятся, репозитории собирают их при чтении, как Preload.
+type state struct {
+	users                map[uint]model.User
+	unavailability       map[uint]model.UserUnavailability
+	teams                map[uint]model.Team
+	settings             map[uint]model.TeamSettings
+	memberships          map[membershipKey]model.UserTeam
+	pullRequests         map[uint]model.PullRequest
+	reviews              map[reviewKey]model.PullRequestReviewer
+	outbox               map[uint64]model.OutboxMessage
+	audit                map[uint64]model.AuditEntry
+	externalPullRequests map[externalKey]model.ExternalPullRequest
+	gitlabUsers          map[string]model.GitLabUser
+	gitlabEvents         map[string]model.GitLabWebhookEvent
+
+	nextUnavailabilityID uint
+	nextTeamID           uint
+	nextPullRequestID    uint
+	nextOutboxID         uint64
+	nextAuditID          uint64
+}
+
+func newState() *state {
+	return &state{
+		users:                map[uint]model.User{},
+		unavailability:       map[uint]model.UserUnavailability{},
+		teams:                map[uint]model.Team{},
+		settings:             map[uint]model.TeamSettings{},
+		memberships:          map[membershipKey]model.UserTeam{},
+		pullRequests:         map[uint]model.PullRequest{},
+		reviews:              map[reviewKey]model.PullRequestReviewer{},
+		outbox:               map[uint64]model.OutboxMessage{},
+		audit:                map[uint64]model.AuditEntry{},
+		externalPullRequests: map[externalKey]model.ExternalPullRequest{},
+		gitlabUsers:          map[string]model.GitLabUser{},
+		gitlabEvents:         map[string]model.GitLabWebhookEvent{},
+	}
+}
+
+// clone копирует таблицы. Строки хранятся без связей, а указатели в них
+// (TeamID, MergedAt, ...) не изменяются на месте, поэтому копии по значению достаточно.
+func (s *state) clone() *state {
+	c := *s
+	c.users = maps.Clone(s.users)
+	c.unavailability = maps.Clone(s.unavailability)
+	c.teams = maps.Clone(s.teams)
+	c.settings = maps.Clone(s.settings)
+	c.memberships = maps.Clone(s.memberships)
+	c.pullRequests = maps.Clone(s.pullRequests)
+	c.reviews = maps.Clone(s.reviews)
+	c.outbox = maps.Clone(s.outbox)
+	c.audit = maps.Clone(s.audit)
+	c.externalPullRequests = maps.Clone(s.externalPullRequests)
+	c.gitlabUsers = maps.Clone(s.gitlabUsers)
+	c.gitlabEvents = maps.Clone(s.gitlabEvents)
+	return &c
+}
+
+// Store - база в памяти. Репозитории из Repositories выполняют каждую операцию
+// как отдельную транзакцию, репозитории внутри Do видят только данные своей
+// транзакции. Транзакции и отдельные операции выполняются строго по очереди,
+// поэтому обращение к репозиториям Store изнутри Do блокируется навсегда.
+type Store struct {
+	mu   sync.Mutex
+	data *state
+}
+
+var _ db.UnitOfWork = (*Store)(nil)
+
+func NewStore() *Store {
+	return &Store{
+		data: newState(),
+	}
+}
+
+// Repositories возвращает репозитории вне транзакции.
+func (s *Store) Repositories() db.Repositories {
+	return newRepositories(&conn{store: s})
+}
+
+func (s *Store) Do(ctx context.Context, fn func(ctx context.Context, repos db.Repositories) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tx := s.data.clone()
+	if err := fn(ctx, newRepositories(&conn{data: tx})); err != nil {
+		return err
+	}
+	s.data = tx
+	return nil
+}
+
+func newRepositories(c *conn) db.Repositories {
+	return db.Repositories{
+		Users:                &userRepository{conn: c},
+		Teams:                &teamRepository{conn: c},
+		PullRequests:         &pullRequestRepository{conn: c},
+		Outbox:               &outboxRepository{conn: c},
+		Audit:                &auditRepository{conn: c},
+		ExternalPullRequests: &externalPullRequestRepository{conn: c},
+		GitLab:               &gitlabRepository{conn: c},
+	}
+}
+
+// conn - соединение репозитория: либо весь Store (store != nil), либо данные
+// открытой транзакции.
+type conn struct {
+	store *Store
+	data  *state
+}
+
+// read выполняет fn над данными соединения.
+func (c *conn) read(fn func(d *state) error) error {
+	if c.store == nil {
+		return fn(c.data)
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	return fn(c.store.data)
+}
+
+// write выполняет fn над данными соединения. Вне транзакции изменения fn
+// применяются, только если она не вернула ошибку.
+func (c *conn) write(fn func(d *state) error) error {
+	if c.store == nil {
+		return fn(c.data)
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	tx := c.store.data.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	c.store.data = tx
+	return nil
+}
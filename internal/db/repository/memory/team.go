@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type teamRepository struct {
+	conn *conn
+}
+
+var _ repository.TeamRepository = (*teamRepository)(nil)
+
+// teamRow - строка teams без связей.
+func teamRow(t model.Team) model.Team {
+	return model.Team{
+		ID:   t.ID,
+		Name: t.Name,
+	}
+}
+
+// Create сохраняет команду и её настройки, если они заданы. Участники
+// добавляются через AddMember.
+func (r *teamRepository) Create(_ context.Context, team *model.Team) error {
+	return r.conn.write(func(d *state) error {
+		if findTeamByName(d, team.Name) != nil {
+			return gorm.ErrDuplicatedKey
+		}
+		if team.ID == 0 {
+			d.nextTeamID++
+			team.ID = d.nextTeamID
+		} else if _, ok := d.teams[team.ID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		d.nextTeamID = max(d.nextTeamID, team.ID)
+
+		d.teams[team.ID] = teamRow(*team)
+		if team.Settings != nil {
+			team.Settings.TeamID = team.ID
+			d.settings[team.ID] = *team.Settings
+		}
+		return nil
+	})
+}
+
+func (r *teamRepository) GetByID(_ context.Context, id uint) (*model.Team, error) {
+	var team model.Team
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.teams[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		team = row
+		return nil
+	})
+	return &team, err
+}
+
+func (r *teamRepository) GetByName(_ context.Context, name string) (*model.Team, error) {
+	var team model.Team
+	err := r.conn.read(func(d *state) error {
+		row := findTeamByName(d, name)
+		if row == nil {
+			return gorm.ErrRecordNotFound
+		}
+		team = *row
+		team.Settings = teamSettings(d, team.ID)
+		return nil
+	})
+	return &team, err
+}
+
+func (r *teamRepository) GetByNameWithMembers(_ context.Context, name string) (*model.Team, error) {
+	var team model.Team
+	err := r.conn.read(func(d *state) error {
+		row := findTeamByName(d, name)
+		if row == nil {
+			return gorm.ErrRecordNotFound
+		}
+		team = withMembers(d, *row)
+		return nil
+	})
+	return &team, err
+}
+
+func (r *teamRepository) GetByIDWithMembers(_ context.Context, id uint) (*model.Team, error) {
+	var team model.Team
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.teams[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		team = withMembers(d, row)
+		return nil
+	})
+	return &team, err
+}
+
+func (r *teamRepository) Update(_ context.Context, team *model.Team) error {
+	return r.conn.write(func(d *state) error {
+		if existing := findTeamByName(d, team.Name); existing != nil && existing.ID != team.ID {
+			return gorm.ErrDuplicatedKey
+		}
+		d.teams[team.ID] = teamRow(*team)
+		d.nextTeamID = max(d.nextTeamID, team.ID)
+		return nil
+	})
+}
+
+func (r *teamRepository) Select(_ context.Context) ([]model.Team, error) {
+	var teams []model.Team
+	err := r.conn.read(func(d *state) error {
+		teams = sortedValues(d.teams)
+		return nil
+	})
+	return teams, err
+}
+
+// Delete удаляет команду вместе с настройками и членством, у PR команды
+// team_id сбрасывается, как ON DELETE в схеме.
+func (r *teamRepository) Delete(_ context.Context, id uint) error {
+	return r.conn.write(func(d *state) error {
+		delete(d.teams, id)
+		delete(d.settings, id)
+		for key := range d.memberships {
+			if key.TeamID == id {
+				delete(d.memberships, key)
+			}
+		}
+		for prID, pr := range d.pullRequests {
+			if pr.TeamID != nil && *pr.TeamID == id {
+				pr.TeamID = nil
+				d.pullRequests[prID] = pr
+			}
+		}
+		return nil
+	})
+}
+
+func (r *teamRepository) ExistsByName(_ context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.conn.read(func(d *state) error {
+		exists = findTeamByName(d, name) != nil
+		return nil
+	})
+	return exists, err
+}
+
+func (r *teamRepository) SaveSettings(_ context.Context, settings *model.TeamSettings) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.teams[settings.TeamID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		d.settings[settings.TeamID] = *settings
+		return nil
+	})
+}
+
+// AddMember добавляет пользователя в команду. Первая команда пользователя
+// становится основной, повторное добавление ничего не меняет.
+func (r *teamRepository) AddMember(_ context.Context, teamID, userID uint) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.teams[teamID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		if _, ok := d.users[userID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+
+		key := membershipKey{UserID: userID, TeamID: teamID}
+		if _, ok := d.memberships[key]; ok {
+			return nil
+		}
+		d.memberships[key] = model.UserTeam{
+			UserID:    userID,
+			TeamID:    teamID,
+			IsPrimary: !hasPrimaryTeam(d, userID),
+		}
+		return nil
+	})
+}
+
+// RemoveMembers исключает пользователей из команды. Если команда была основной,
+// основной становится оставшаяся команда с наименьшим id.
+func (r *teamRepository) RemoveMembers(_ context.Context, teamID uint, userIDs []uint) error {
+	return r.conn.write(func(d *state) error {
+		for _, userID := range userIDs {
+			delete(d.memberships, membershipKey{UserID: userID, TeamID: teamID})
+		}
+
+		for _, m := range sortedMemberships(d) {
+			if !slices.Contains(userIDs, m.UserID) || hasPrimaryTeam(d, m.UserID) {
+				continue
+			}
+			m.IsPrimary = true
+			d.memberships[membershipKey{UserID: m.UserID, TeamID: m.TeamID}] = m
+		}
+		return nil
+	})
+}
+
+func findTeamByName(d *state, name string) *model.Team {
+	for _, team := range d.teams {
+		if team.Name == name {
+			return &team
+		}
+	}
+	return nil
+}
+
+func teamSettings(d *state, teamID uint) *model.TeamSettings {
+	settings, ok := d.settings[teamID]
+	if !ok {
+		return nil
+	}
+	return &settings
+}
+
+// withMembers дополняет строку команды участниками (по возрастанию id) и настройками.
+func withMembers(d *state, team model.Team) model.Team {
+	team.Members = []model.User{}
+	for _, m := range sortedMemberships(d) {
+		if m.TeamID == team.ID {
+			team.Members = append(team.Members, d.users[m.UserID])
+		}
+	}
+	team.Settings = teamSettings(d, team.ID)
+	return team
+}
+
+func hasPrimaryTeam(d *state, userID uint) bool {
+	for key, m := range d.memberships {
+		if key.UserID == userID && m.IsPrimary {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type userRepository struct {
+	conn *conn
+}
+
+var _ repository.UserRepository = (*userRepository)(nil)
+
+// userRow - строка users без связей.
+func userRow(u model.User) model.User {
+	return model.User{
+		ID:       u.ID,
+		Name:     u.Name,
+		IsActive: u.IsActive,
+	}
+}
+
+func (r *userRepository) Create(_ context.Context, user *model.User) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.users[user.ID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		d.users[user.ID] = userRow(*user)
+		return nil
+	})
+}
+
+func (r *userRepository) GetByID(_ context.Context, id uint) (*model.User, error) {
+	var user model.User
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.users[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		user = row
+		return nil
+	})
+	return &user, err
+}
+
+func (r *userRepository) GetByIDWithTeams(_ context.Context, id uint) (*model.User, error) {
+	var user model.User
+	err := r.conn.read(func(d *state) error {
+		row, ok := d.users[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		user = row
+		user.Teams = []model.Team{}
+		user.Memberships = []model.UserTeam{}
+		for _, m := range sortedMemberships(d) {
+			if m.UserID != id {
+				continue
+			}
+			team := d.teams[m.TeamID]
+			user.Teams = append(user.Teams, team)
+			m.Team = team
+			user.Memberships = append(user.Memberships, m)
+		}
+		slices.SortFunc(user.Teams, func(a, b model.Team) int { return cmp.Compare(a.ID, b.ID) })
+		slices.SortStableFunc(user.Memberships, func(a, b model.UserTeam) int {
+			if a.IsPrimary != b.IsPrimary {
+				if a.IsPrimary {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(a.TeamID, b.TeamID)
+		})
+		return nil
+	})
+	return &user, err
+}
+
+func (r *userRepository) Update(_ context.Context, user *model.User) error {
+	return r.conn.write(func(d *state) error {
+		d.users[user.ID] = userRow(*user)
+		return nil
+	})
+}
+
+func (r *userRepository) Select(_ context.Context) ([]model.User, error) {
+	var users []model.User
+	err := r.conn.read(func(d *state) error {
+		users = sortedValues(d.users)
+		return nil
+	})
+	return users, err
+}
+
+func (r *userRepository) Delete(_ context.Context, id uint) error {
+	return r.conn.write(func(d *state) error {
+		for _, pr := range d.pullRequests {
+			if pr.AuthorID == id {
+				return gorm.ErrForeignKeyViolated
+			}
+		}
+		for key := range d.reviews {
+			if key.ReviewerID == id {
+				return gorm.ErrForeignKeyViolated
+			}
+		}
+		delete(d.users, id)
+		for key := range d.memberships {
+			if key.UserID == id {
+				delete(d.memberships, key)
+			}
+		}
+		for periodID, period := range d.unavailability {
+			if period.UserID == id {
+				delete(d.unavailability, periodID)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) UpsertUser(_ context.Context, id uint, name string, isActive bool) (*model.User, error) {
+	user := &model.User{
+		ID:       id,
+		Name:     name,
+		IsActive: isActive,
+	}
+	err := r.conn.write(func(d *state) error {
+		d.users[id] = *user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) SetActive(_ context.Context, ids []uint, isActive bool) error {
+	return r.conn.write(func(d *state) error {
+		for _, id := range ids {
+			if user, ok := d.users[id]; ok {
+				user.IsActive = isActive
+				d.users[id] = user
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) AddUnavailability(_ context.Context, period *model.UserUnavailability) error {
+	return r.conn.write(func(d *state) error {
+		if _, ok := d.users[period.UserID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+		d.nextUnavailabilityID++
+		period.ID = d.nextUnavailabilityID
+		row := *period
+		row.User = model.User{}
+		d.unavailability[row.ID] = row
+		return nil
+	})
+}
+
+func (r *userRepository) ListUnavailability(_ context.Context, userID uint) ([]model.UserUnavailability, error) {
+	var periods []model.UserUnavailability
+	err := r.conn.read(func(d *state) error {
+		for _, period := range sortedValues(d.unavailability) {
+			if period.UserID == userID {
+				periods = append(periods, period)
+			}
+		}
+		slices.SortStableFunc(periods, func(a, b model.UserUnavailability) int {
+			return a.StartsAt.Compare(b.StartsAt)
+		})
+		return nil
+	})
+	return periods, err
+}
+
+func (r *userRepository) DeleteUnavailability(_ context.Context, userID, id uint) (bool, error) {
+	var deleted bool
+	err := r.conn.write(func(d *state) error {
+		period, ok := d.unavailability[id]
+		if !ok || period.UserID != userID {
+			return nil
+		}
+		delete(d.unavailability, id)
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *userRepository) GetUnavailableIDs(_ context.Context, userIDs []uint, at time.Time) (map[uint]bool, error) {
+	unavailable := map[uint]bool{}
+	err := r.conn.read(func(d *state) error {
+		for _, period := range d.unavailability {
+			if slices.Contains(userIDs, period.UserID) && !period.StartsAt.After(at) && period.EndsAt.After(at) {
+				unavailable[period.UserID] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unavailable, nil
+}
+
+// sortedValues возвращает строки таблицы в порядке первичного ключа.
+func sortedValues[K cmp.Ordered, V any](rows map[K]V) []V {
+	keys := slices.Sorted(maps.Keys(rows))
+	values := make([]V, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, rows[key])
+	}
+	return values
+}
+
+// sortedMemberships возвращает user_team в порядке (user_id, team_id).
+func sortedMemberships(d *state) []model.UserTeam {
+	memberships := slices.Collect(maps.Values(d.memberships))
+	slices.SortFunc(memberships, func(a, b model.UserTeam) int {
+		return cmp.Or(cmp.Compare(a.UserID, b.UserID), cmp.Compare(a.TeamID, b.TeamID))
+	})
+	return memberships
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/events"
+)
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, evs ...events.Event) error
+	// ProcessPending блокирует до limit неотправленных сообщений (FOR UPDATE SKIP LOCKED),
+	// передаёт их в handle и сохраняет изменённые handle поля attempts, next_attempt_at,
+	// last_error и delivered_at в той же транзакции. В SQLite сообщения обрабатываются
+	// без транзакции: она заблокировала бы всю базу, пока работают получатели.
+	ProcessPending(ctx context.Context, now time.Time, limit int, handle func(msgs []model.OutboxMessage) error) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) WithTx(tx *gorm.DB) *outboxRepository {
+	return &outboxRepository{
+		db: tx,
+	}
+}
+
+func (r *outboxRepository) Enqueue(ctx context.Context, evs ...events.Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	msgs := make([]model.OutboxMessage, 0, len(evs))
+	for _, ev := range evs {
+		payload, err := json.Marshal(ev.Data)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, model.OutboxMessage{
+			EventType:     string(ev.Type),
+			Payload:       string(payload),
+			OccurredAt:    ev.OccurredAt,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return r.db.WithContext(ctx).Create(&msgs).Error
+}
+
+func (r *outboxRepository) ProcessPending(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	handle func(msgs []model.OutboxMessage) error,
+) error {
+	// С файлом SQLite работает один экземпляр сервиса, так что SKIP LOCKED там не нужен,
+	// а получатель webhooks пишет в базу через другое соединение и ждал бы блокировку
+	// этой же транзакции.
+	if isSQLite(r.db) {
+		return processPending(r.db.WithContext(ctx), now, limit, handle)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return processPending(tx, now, limit, handle)
+	})
+}
+
+func processPending(
+	tx *gorm.DB,
+	now time.Time,
+	limit int,
+	handle func(msgs []model.OutboxMessage) error,
+) error {
+	var msgs []model.OutboxMessage
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("delivered_at IS NULL AND next_attempt_at <= ?", now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&msgs).Error
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if err := handle(msgs); err != nil {
+		return err
+	}
+
+	for i := range msgs {
+		err := tx.
+			Model(&model.OutboxMessage{}).
+			Where("id = ?", msgs[i].ID).
+			Updates(map[string]any{
+				"attempts":        msgs[i].Attempts,
+				"next_attempt_at": msgs[i].NextAttemptAt,
+				"last_error":      msgs[i].LastError,
+				"delivered_at":    msgs[i].DeliveredAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"go-rest-api/internal/db/model"
 )
@@ -12,15 +14,37 @@ type PullRequestRepository interface {
 	Create(ctx context.Context, pr *model.PullRequest) error
 	GetByID(ctx context.Context, id uint) (*model.PullRequest, error)
 	GetByIDWithRelations(ctx context.Context, id uint) (*model.PullRequest, error)
+	// GetByIDForUpdate блокирует строку PR (SELECT ... FOR UPDATE) до конца текущей
+	// транзакции и возвращает PR со связями. Вне транзакции блокировка снимается сразу.
+	GetByIDForUpdate(ctx context.Context, id uint) (*model.PullRequest, error)
 	Update(ctx context.Context, pr *model.PullRequest) error
 	Select(ctx context.Context) ([]model.PullRequest, error)
 	Delete(ctx context.Context, id uint) error
 	ExistsByID(ctx context.Context, id uint) (bool, error)
 	GetReviewerPRs(ctx context.Context, reviewerID uint) ([]model.PullRequest, error)
+	List(ctx context.Context, q PullRequestQuery) ([]model.PullRequest, error)
 
 	AddReviewer(ctx context.Context, prID, reviewerID uint) error
 	RemoveReviewer(ctx context.Context, prID, reviewerID uint) error
 	IsReviewerAssigned(ctx context.Context, prID, reviewerID uint) (bool, error)
+	SetReviewState(ctx context.Context, prID, reviewerID uint, state model.ReviewState, reviewedAt time.Time) error
+	CountOpenReviews(ctx context.Context, reviewerIDs []uint) (map[uint]int64, error)
+	ListOpenAssignments(ctx context.Context, reviewerIDs []uint) ([]ReviewAssignment, error)
+	ReplaceReviewers(ctx context.Context, moves []ReviewerMove) error
+}
+
+// ReviewAssignment - назначение ревьювера на OPEN PR вместе с автором и командой PR.
+type ReviewAssignment struct {
+	PrID       uint
+	ReviewerID uint
+	AuthorID   uint
+	TeamID     *uint
+}
+
+type ReviewerMove struct {
+	PrID          uint
+	OldReviewerID uint
+	NewReviewerID uint
 }
 
 type pullRequestRepository struct {
@@ -35,15 +59,52 @@ func NewPullRequestRepository(db *gorm.DB) PullRequestRepository {
 	}
 }
 
+// Create сохраняет PR. PR без id получает следующее значение последовательности;
+// значения, уже занятые PR с явно заданным id, пропускаются.
+func (r *pullRequestRepository) Create(ctx context.Context, pr *model.PullRequest) error {
+	if pr.ID != 0 {
+		return r.db.WithContext(ctx).Omit(clause.Associations).Create(pr).Error
+	}
+
+	for {
+		result := r.db.WithContext(ctx).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(pr)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		pr.ID = 0
+	}
+}
+
 func (r *pullRequestRepository) GetByIDWithRelations(ctx context.Context, id uint) (*model.PullRequest, error) {
 	var pr model.PullRequest
 	err := r.db.WithContext(ctx).
 		Preload("Author").
+		Preload("Team").
 		Preload("Reviewers").
+		Preload("Reviews").
 		First(&pr, id).Error
 	return &pr, err
 }
 
+func (r *pullRequestRepository) GetByIDForUpdate(ctx context.Context, id uint) (*model.PullRequest, error) {
+	var locked model.PullRequest
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&locked, id).Error
+	if err != nil {
+		return &locked, err
+	}
+	return r.GetByIDWithRelations(ctx, id)
+}
+
+func (r *pullRequestRepository) Update(ctx context.Context, pr *model.PullRequest) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(pr).Error
+}
+
 func (r *pullRequestRepository) ExistsByID(ctx context.Context, id uint) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -63,10 +124,22 @@ func (r *pullRequestRepository) GetReviewerPRs(ctx context.Context, reviewerID u
 	return prs, err
 }
 
+func (r *pullRequestRepository) List(ctx context.Context, q PullRequestQuery) ([]model.PullRequest, error) {
+	var prs []model.PullRequest
+	err := r.db.WithContext(ctx).
+		Scopes(filterPullRequests(q.Filter), paginatePullRequests(q)).
+		Preload("Team").
+		Preload("Reviewers").
+		Preload("Reviews").
+		Find(&prs).Error
+	return prs, err
+}
+
 func (r *pullRequestRepository) AddReviewer(ctx context.Context, prID, reviewerID uint) error {
 	prReviewer := &model.PullRequestReviewer{
 		PrID:       prID,
 		ReviewerID: reviewerID,
+		State:      model.ReviewStatePending,
 	}
 	return r.db.WithContext(ctx).Create(prReviewer).Error
 }
@@ -86,6 +159,86 @@ func (r *pullRequestRepository) IsReviewerAssigned(ctx context.Context, prID, re
 	return count > 0, err
 }
 
+func (r *pullRequestRepository) SetReviewState(ctx context.Context, prID, reviewerID uint, state model.ReviewState, reviewedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.PullRequestReviewer{}).
+		Where("pr_id = ? AND reviewer_id = ?", prID, reviewerID).
+		Updates(map[string]any{
+			"state":       state,
+			"reviewed_at": reviewedAt,
+		}).Error
+}
+
+func (r *pullRequestRepository) CountOpenReviews(ctx context.Context, reviewerIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		ReviewerID uint
+		Count      int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.PullRequestReviewer{}).
+		Select("pull_request_reviewer.reviewer_id, COUNT(*) AS count").
+		Joins("JOIN pull_requests ON pull_requests.id = pull_request_reviewer.pr_id").
+		Where("pull_request_reviewer.reviewer_id IN ? AND pull_requests.status = ?", reviewerIDs, model.PrStatusOpen).
+		Group("pull_request_reviewer.reviewer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ReviewerID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListOpenAssignments возвращает все назначения на OPEN PR, где ревьювером
+// указан хотя бы один из reviewerIDs, включая остальных ревьюверов этих PR.
+func (r *pullRequestRepository) ListOpenAssignments(ctx context.Context, reviewerIDs []uint) ([]ReviewAssignment, error) {
+	var assignments []ReviewAssignment
+	err := r.db.WithContext(ctx).
+		Model(&model.PullRequestReviewer{}).
+		Select("pull_request_reviewer.pr_id, pull_request_reviewer.reviewer_id, pull_requests.author_id, pull_requests.team_id").
+		Joins("JOIN pull_requests ON pull_requests.id = pull_request_reviewer.pr_id").
+		Where("pull_requests.status = ?", model.PrStatusOpen).
+		Where("pull_request_reviewer.pr_id IN (?)",
+			r.db.Model(&model.PullRequestReviewer{}).Select("pr_id").Where("reviewer_id IN ?", reviewerIDs),
+		).
+		Order("pull_request_reviewer.pr_id, pull_request_reviewer.reviewer_id").
+		Scan(&assignments).Error
+	return assignments, err
+}
+
+func (r *pullRequestRepository) ReplaceReviewers(ctx context.Context, moves []ReviewerMove) error {
+	if len(moves) == 0 {
+		return nil
+	}
+
+	// (pr_id, reviewer_id) IN ((?, ?), ...) не поддерживается SQLite.
+	removed := make([]clause.Expression, len(moves))
+	added := make([]model.PullRequestReviewer, len(moves))
+	for i, move := range moves {
+		removed[i] = clause.And(
+			clause.Eq{Column: "pr_id", Value: move.PrID},
+			clause.Eq{Column: "reviewer_id", Value: move.OldReviewerID},
+		)
+		added[i] = model.PullRequestReviewer{
+			PrID:       move.PrID,
+			ReviewerID: move.NewReviewerID,
+			State:      model.ReviewStatePending,
+		}
+	}
+
+	db := r.db.WithContext(ctx)
+	err := db.Where(clause.Or(removed...)).
+		Delete(&model.PullRequestReviewer{}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Omit("PullRequest", "Reviewer").CreateInBatches(added, 500).Error
+}
+
 func (r *pullRequestRepository) WithTx(tx *gorm.DB) *pullRequestRepository {
 	return &pullRequestRepository{
 		BaseRepository: r.BaseRepository.WithTx(tx),
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/db/model"
+)
+
+type PullRequestSortField string
+
+const (
+	PullRequestSortByID        PullRequestSortField = "id"
+	PullRequestSortByCreatedAt PullRequestSortField = "created_at"
+)
+
+type PullRequestFilter struct {
+	Status      *model.PrStatus
+	AuthorID    *uint
+	ReviewerID  *uint
+	TeamName    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// PullRequestCursor - позиция последней строки предыдущей страницы.
+type PullRequestCursor struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+type PullRequestQuery struct {
+	Filter PullRequestFilter
+	SortBy PullRequestSortField
+	Desc   bool
+	After  *PullRequestCursor
+	Limit  int
+}
+
+func filterPullRequests(f PullRequestFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if f.Status != nil {
+			db = db.Where("pull_requests.status = ?", *f.Status)
+		}
+		if f.AuthorID != nil {
+			db = db.Where("pull_requests.author_id = ?", *f.AuthorID)
+		}
+		if f.ReviewerID != nil {
+			db = db.Where(
+				"EXISTS (SELECT 1 FROM pull_request_reviewer WHERE pull_request_reviewer.pr_id = pull_requests.id AND pull_request_reviewer.reviewer_id = ?)",
+				*f.ReviewerID,
+			)
+		}
+		if f.TeamName != "" {
+			db = db.Where(
+				"pull_requests.team_id IN (SELECT id FROM teams WHERE name = ?)",
+				f.TeamName,
+			)
+		}
+		if f.CreatedFrom != nil {
+			db = db.Where("pull_requests.created_at >= ?", *f.CreatedFrom)
+		}
+		if f.CreatedTo != nil {
+			db = db.Where("pull_requests.created_at < ?", *f.CreatedTo)
+		}
+		return db
+	}
+}
+
+func paginatePullRequests(q PullRequestQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		cmp, order := ">", "ASC"
+		if q.Desc {
+			cmp, order = "<", "DESC"
+		}
+
+		if q.SortBy == PullRequestSortByCreatedAt {
+			if q.After != nil {
+				db = db.Where(
+					"(pull_requests.created_at "+cmp+" ? OR (pull_requests.created_at = ? AND pull_requests.id "+cmp+" ?))",
+					q.After.CreatedAt, q.After.CreatedAt, q.After.ID,
+				)
+			}
+			db = db.Order("pull_requests.created_at " + order).Order("pull_requests.id " + order)
+		} else {
+			if q.After != nil {
+				db = db.Where("pull_requests.id "+cmp+" ?", q.After.ID)
+			}
+			db = db.Order("pull_requests.id " + order)
+		}
+
+		if q.Limit > 0 {
+			db = db.Limit(q.Limit)
+		}
+		return db
+	}
+}
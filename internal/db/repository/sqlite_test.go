@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/migrate"
+	"go-rest-api/internal/db/model"
+)
+
+// openSQLite создаёт базу SQLite во временном каталоге и применяет к ней
+// встроенные миграции.
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	provider, err := migrate.NewProvider(sqlDB, config.DriverSQLite)
+	if err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return db
+}
+
+func mustExec(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+}
+
+func TestSQLiteRemoveMembersPromotesPrimaryTeam(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	users, teams := NewUserRepository(db), NewTeamRepository(db)
+
+	_, err := users.UpsertUser(ctx, 1, "alice", false)
+	mustExec(t, err)
+	for _, name := range []string{"backend", "frontend", "infra"} {
+		team := &model.Team{Name: name}
+		mustExec(t, teams.Create(ctx, team))
+		mustExec(t, teams.AddMember(ctx, team.ID, 1))
+	}
+
+	mustExec(t, teams.RemoveMembers(ctx, 1, []uint{1}))
+
+	user, err := users.GetByIDWithTeams(ctx, 1)
+	mustExec(t, err)
+	if user.IsActive {
+		t.Error("user was created inactive but is active")
+	}
+	if len(user.Memberships) != 2 || !user.Memberships[0].IsPrimary || user.Memberships[0].TeamID != 2 {
+		t.Fatalf("memberships = %+v, want frontend (id 2) primary", user.Memberships)
+	}
+}
+
+func TestSQLiteReplaceReviewers(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	users, prs := NewUserRepository(db), NewPullRequestRepository(db)
+
+	for id := uint(1); id <= 4; id++ {
+		_, err := users.UpsertUser(ctx, id, "user", true)
+		mustExec(t, err)
+	}
+	for id := uint(1); id <= 2; id++ {
+		mustExec(t, prs.Create(ctx, &model.PullRequest{ID: id, Title: "pr", AuthorID: 1}))
+		mustExec(t, prs.AddReviewer(ctx, id, 2))
+		mustExec(t, prs.AddReviewer(ctx, id, 3))
+	}
+
+	mustExec(t, prs.ReplaceReviewers(ctx, []ReviewerMove{
+		{PrID: 1, OldReviewerID: 2, NewReviewerID: 4},
+		{PrID: 2, OldReviewerID: 3, NewReviewerID: 4},
+	}))
+
+	for id, want := range map[uint][]uint{1: {3, 4}, 2: {2, 4}} {
+		pr, err := prs.GetByIDWithRelations(ctx, id)
+		mustExec(t, err)
+		var got []uint
+		for _, reviewer := range pr.Reviewers {
+			got = append(got, reviewer.ID)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("pr %d reviewers = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestSQLitePullRequestUpdateKeepsUpdatedAt(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	users, prs := NewUserRepository(db), NewPullRequestRepository(db)
+
+	_, err := users.UpsertUser(ctx, 1, "user", true)
+	mustExec(t, err)
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mustExec(t, prs.Create(ctx, &model.PullRequest{ID: 1, Title: "pr", AuthorID: 1, CreatedAt: created, UpdatedAt: created}))
+
+	pr, err := prs.GetByIDWithRelations(ctx, 1)
+	mustExec(t, err)
+	merged := created.Add(time.Hour)
+	pr.Status, pr.MergedAt, pr.UpdatedAt = model.PrStatusMerged, &merged, merged
+	mustExec(t, prs.Update(ctx, pr))
+
+	if !pr.UpdatedAt.Equal(merged) {
+		t.Errorf("UpdatedAt after Update = %v, want %v", pr.UpdatedAt, merged)
+	}
+	stored, err := prs.GetByIDWithRelations(ctx, 1)
+	mustExec(t, err)
+	if !stored.UpdatedAt.Equal(merged) || !stored.MergedAt.Equal(merged) {
+		t.Errorf("stored UpdatedAt = %v, MergedAt = %v, want %v", stored.UpdatedAt, stored.MergedAt, merged)
+	}
+}
+
+func TestSQLiteExternalPullRequests(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	users, prs, links := NewUserRepository(db), NewPullRequestRepository(db), NewExternalPullRequestRepository(db)
+
+	_, err := users.UpsertUser(ctx, 1, "alice", true)
+	mustExec(t, err)
+	mustExec(t, prs.Create(ctx, &model.PullRequest{ID: 1, Title: "pr", AuthorID: 1}))
+
+	pr := &model.PullRequest{Title: "external", AuthorID: 1}
+	mustExec(t, prs.Create(ctx, pr))
+	if pr.ID != 2 {
+		t.Fatalf("allocated id = %d, want 2", pr.ID)
+	}
+
+	link := &model.ExternalPullRequest{Provider: model.ExternalProviderGitHub, Repository: "acme/service", Number: 42, PrID: pr.ID}
+	mustExec(t, links.Create(ctx, link))
+
+	duplicate := *link
+	duplicate.PrID = 1
+	if err := links.Create(ctx, &duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("duplicate link error = %v, want ErrDuplicatedKey", err)
+	}
+
+	got, err := links.Get(ctx, model.ExternalProviderGitHub, "acme/service", 42)
+	mustExec(t, err)
+	if got.PrID != pr.ID {
+		t.Errorf("link points to %d, want %d", got.PrID, pr.ID)
+	}
+	if _, err := links.Get(ctx, model.ExternalProviderGitHub, "acme/other", 42); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("other repository error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestSQLiteAuditFilterByTargetID(t *testing.T) {
+	ctx := context.Background()
+	audit := NewAuditRepository(openSQLite(t))
+
+	now := time.Now().UTC()
+	for _, entry := range []model.AuditEntry{
+		{EntityID: "backend", TargetIDs: model.TargetIDs{"u1", "u2"}},
+		{EntityID: "pr-1", TargetIDs: model.TargetIDs{"u3"}},
+		{EntityID: "u2", TargetIDs: model.TargetIDs{}},
+	} {
+		entry.OccurredAt = now
+		entry.Actor = "admin"
+		entry.Operation = "test"
+		entry.EntityType = model.AuditEntityTeam
+		entry.Diff = "{}"
+		mustExec(t, audit.Create(ctx, &entry))
+	}
+
+	entries, err := audit.List(ctx, AuditQuery{Filter: AuditFilter{EntityID: "u2"}})
+	mustExec(t, err)
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.EntityID)
+	}
+	if want := []string{"u2", "backend"}; !slices.Equal(got, want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+}
+
+func TestSQLiteGitLabRecordEventRejectsDuplicate(t *testing.T) {
+	ctx := context.Background()
+	gitlab := NewGitLabRepository(openSQLite(t))
+
+	mustExec(t, gitlab.RecordEvent(ctx, "event-1", time.Now()))
+	if err := gitlab.RecordEvent(ctx, "event-1", time.Now()); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("duplicate event error = %v, want ErrDuplicatedKey", err)
+	}
+}
+
+func TestSQLiteClaimDueDeliveries(t *testing.T) {
+	ctx := context.Background()
+	webhooks := NewWebhookRepository(openSQLite(t))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	sub := &model.WebhookSubscription{URL: "http://localhost:9/hook", Secret: "s3cret", EventTypes: model.EventTypes{"pr.created"}, CreatedAt: now}
+	mustExec(t, webhooks.CreateSubscription(ctx, sub))
+	mustExec(t, webhooks.CreateDeliveries(ctx, []model.WebhookDelivery{
+		{SubscriptionID: sub.ID, EventType: "pr.created", Payload: "{}", Status: model.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now},
+		{SubscriptionID: sub.ID, EventType: "pr.created", Payload: "{}", Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(time.Hour), CreatedAt: now},
+	}))
+
+	claimed, err := webhooks.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+	mustExec(t, err)
+	if len(claimed) != 1 || claimed[0].Subscription.URL != sub.URL {
+		t.Fatalf("claimed = %+v, want the due delivery with its subscription", claimed)
+	}
+
+	again, err := webhooks.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+	mustExec(t, err)
+	if len(again) != 0 {
+		t.Errorf("claimed delivery returned again: %+v", again)
+	}
+
+	// экземпляр не сохранил результат - по истечении аренды доставка снова в работе
+	expired, err := webhooks.ClaimDueDeliveries(ctx, now.Add(time.Minute), time.Minute, 10)
+	mustExec(t, err)
+	if len(expired) != 1 || expired[0].ID != claimed[0].ID {
+		t.Errorf("after lease = %+v, want delivery %d", expired, claimed[0].ID)
+	}
+}
+
+func TestSQLiteIdempotencyPendingLease(t *testing.T) {
+	ctx := context.Background()
+	keys := NewIdempotencyRepository(openSQLite(t))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reserve := func(at time.Time, token string) *model.IdempotencyKey {
+		t.Helper()
+		existing, err := keys.Reserve(ctx, &model.IdempotencyKey{Scope: "ci", Key: "k1", RequestHash: "h", LeaseToken: token, CreatedAt: at, ExpiresAt: at.Add(time.Minute)})
+		mustExec(t, err)
+		return existing
+	}
+	complete := func(token string) error {
+		status := 201
+		return keys.Complete(ctx, &model.IdempotencyKey{
+			Scope: "ci", Key: "k1", RequestHash: "h", LeaseToken: token,
+			StatusCode: &status, ContentType: "application/json", ResponseBody: []byte("{}"),
+			ExpiresAt: now.Add(24 * time.Hour),
+		})
+	}
+
+	reserve(now, "first")
+	if existing := reserve(now.Add(30*time.Second), "second"); existing == nil || existing.Completed() {
+		t.Fatalf("reservation within lease = %+v, want pending key", existing)
+	}
+
+	// экземпляр не завершил запрос - по истечении аренды ключ занимается заново
+	if existing := reserve(now.Add(time.Minute), "second"); existing != nil {
+		t.Fatalf("reservation after lease = %+v, want new key", existing)
+	}
+
+	// первый запрос потерял аренду: ни сохранить ответ, ни освободить ключ он не может
+	if err := complete("first"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("complete with stale lease err = %v, want ErrRecordNotFound", err)
+	}
+	mustExec(t, keys.Release(ctx, "ci", "k1", "first"))
+
+	mustExec(t, complete("second"))
+	if existing := reserve(now.Add(time.Hour), "third"); existing == nil || !existing.Completed() {
+		t.Errorf("reservation after completion = %+v, want stored response", existing)
+	}
+	if err := complete("second"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("repeated complete err = %v, want ErrRecordNotFound", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"go-rest-api/internal/db/model"
 )
@@ -13,10 +14,14 @@ type TeamRepository interface {
 	GetByID(ctx context.Context, id uint) (*model.Team, error)
 	GetByName(ctx context.Context, name string) (*model.Team, error)
 	GetByNameWithMembers(ctx context.Context, name string) (*model.Team, error)
+	GetByIDWithMembers(ctx context.Context, id uint) (*model.Team, error)
 	Update(ctx context.Context, team *model.Team) error
 	Select(ctx context.Context) ([]model.Team, error)
 	Delete(ctx context.Context, id uint) error
 	ExistsByName(ctx context.Context, name string) (bool, error)
+	SaveSettings(ctx context.Context, settings *model.TeamSettings) error
+	AddMember(ctx context.Context, teamID, userID uint) error
+	RemoveMembers(ctx context.Context, teamID uint, userIDs []uint) error
 }
 
 type teamRepository struct {
@@ -33,7 +38,10 @@ func NewTeamRepository(db *gorm.DB) TeamRepository {
 
 func (r *teamRepository) GetByName(ctx context.Context, name string) (*model.Team, error) {
 	var team model.Team
-	err := r.db.WithContext(ctx).Where("name = ?", name).First(&team).Error
+	err := r.db.WithContext(ctx).
+		Preload("Settings").
+		Where("name = ?", name).
+		First(&team).Error
 	return &team, err
 }
 
@@ -41,11 +49,21 @@ func (r *teamRepository) GetByNameWithMembers(ctx context.Context, name string)
 	var team model.Team
 	err := r.db.WithContext(ctx).
 		Preload("Members").
+		Preload("Settings").
 		Where("name = ?", name).
 		First(&team).Error
 	return &team, err
 }
 
+func (r *teamRepository) GetByIDWithMembers(ctx context.Context, id uint) (*model.Team, error) {
+	var team model.Team
+	err := r.db.WithContext(ctx).
+		Preload("Members").
+		Preload("Settings").
+		First(&team, id).Error
+	return &team, err
+}
+
 func (r *teamRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -55,6 +73,56 @@ func (r *teamRepository) ExistsByName(ctx context.Context, name string) (bool, e
 	return count > 0, err
 }
 
+func (r *teamRepository) SaveSettings(ctx context.Context, settings *model.TeamSettings) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "team_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"min_reviewers", "max_reviewers", "reviewer_strategy", "required_approvals"}),
+		}).
+		Create(settings).Error
+}
+
+func (r *teamRepository) Update(ctx context.Context, team *model.Team) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(team).Error
+}
+
+// AddMember добавляет пользователя в команду. Первая команда пользователя
+// становится основной, повторное добавление ничего не меняет.
+func (r *teamRepository) AddMember(ctx context.Context, teamID, userID uint) error {
+	return r.db.WithContext(ctx).Exec(
+		`INSERT INTO user_team (user_id, team_id, is_primary)
+		VALUES (?, ?, NOT EXISTS (SELECT 1 FROM user_team WHERE user_id = ? AND is_primary))
+		ON CONFLICT (user_id, team_id) DO NOTHING`,
+		userID, teamID, userID,
+	).Error
+}
+
+// RemoveMembers исключает пользователей из команды. Если команда была основной,
+// основной становится оставшаяся команда с наименьшим id.
+func (r *teamRepository) RemoveMembers(ctx context.Context, teamID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	db := r.db.WithContext(ctx)
+	err := db.Where("team_id = ? AND user_id IN ?", teamID, userIDs).
+		Delete(&model.UserTeam{}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(
+		`UPDATE user_team SET is_primary = TRUE
+		WHERE (user_id, team_id) IN (
+			SELECT user_id, MIN(team_id) FROM user_team
+			WHERE user_id IN ?
+			GROUP BY user_id
+			HAVING SUM(CASE WHEN is_primary THEN 1 ELSE 0 END) = 0
+		)`,
+		userIDs,
+	).Error
+}
+
 func (r *teamRepository) WithTx(tx *gorm.DB) *teamRepository {
 	return &teamRepository{
 		BaseRepository: r.BaseRepository.WithTx(tx),
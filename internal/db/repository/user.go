@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -17,6 +18,12 @@ type UserRepository interface {
 	Select(ctx context.Context) ([]model.User, error)
 	Delete(ctx context.Context, id uint) error
 	UpsertUser(ctx context.Context, id uint, name string, isActive bool) (*model.User, error)
+	SetActive(ctx context.Context, ids []uint, isActive bool) error
+
+	AddUnavailability(ctx context.Context, period *model.UserUnavailability) error
+	ListUnavailability(ctx context.Context, userID uint) ([]model.UserUnavailability, error)
+	DeleteUnavailability(ctx context.Context, userID, id uint) (bool, error)
+	GetUnavailableIDs(ctx context.Context, userIDs []uint, at time.Time) (map[uint]bool, error)
 }
 
 type userRepository struct {
@@ -35,10 +42,18 @@ func (r *userRepository) GetByIDWithTeams(ctx context.Context, id uint) (*model.
 	var user model.User
 	err := r.db.WithContext(ctx).
 		Preload("Teams").
+		Preload("Memberships", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_primary DESC, team_id")
+		}).
+		Preload("Memberships.Team").
 		First(&user, id).Error
 	return &user, err
 }
 
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(user).Error
+}
+
 func (r *userRepository) UpsertUser(ctx context.Context, id uint, name string, isActive bool) (*model.User, error) {
 	user := &model.User{
 		ID:       id,
@@ -60,6 +75,51 @@ func (r *userRepository) UpsertUser(ctx context.Context, id uint, name string, i
 	return user, nil
 }
 
+func (r *userRepository) SetActive(ctx context.Context, ids []uint, isActive bool) error {
+	return r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id IN ?", ids).
+		Update("is_active", isActive).Error
+}
+
+func (r *userRepository) AddUnavailability(ctx context.Context, period *model.UserUnavailability) error {
+	return r.db.WithContext(ctx).Omit("User").Create(period).Error
+}
+
+func (r *userRepository) ListUnavailability(ctx context.Context, userID uint) ([]model.UserUnavailability, error) {
+	var periods []model.UserUnavailability
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("starts_at").
+		Find(&periods).Error
+	return periods, err
+}
+
+func (r *userRepository) DeleteUnavailability(ctx context.Context, userID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&model.UserUnavailability{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRepository) GetUnavailableIDs(ctx context.Context, userIDs []uint, at time.Time) (map[uint]bool, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&model.UserUnavailability{}).
+		Distinct("user_id").
+		Where("user_id IN ? AND starts_at <= ? AND ends_at > ?", userIDs, at, at).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unavailable[id] = true
+	}
+	return unavailable, nil
+}
+
 func (r *userRepository) WithTx(tx *gorm.DB) *userRepository {
 	return &userRepository{
 		BaseRepository: r.BaseRepository.WithTx(tx),
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"go-rest-api/internal/db/model"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uint) (bool, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id uint64, attempts int, at time.Time) error
+	ScheduleRetry(ctx context.Context, id uint64, attempts int, next time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uint64, attempts int, lastError string) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Order("id").
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&model.WebhookSubscription{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries забирает до limit PENDING доставок, время попытки которых
+// наступило, вместе с подпиской (URL и секрет). Доставки блокируются
+// FOR UPDATE SKIP LOCKED, и в той же транзакции их next_attempt_at переносится
+// на now + lease, поэтому другие экземпляры сервиса не отправят их повторно.
+// Если экземпляр упал, не сохранив результат, доставки вернутся в работу после
+// истечения lease. Транзакция не ждёт получателей, поэтому она нужна и в SQLite.
+func (r *webhookRepository) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(deliveries))
+		subIDs := make([]uint, 0, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+			subIDs = append(subIDs, d.SubscriptionID)
+		}
+		err = tx.
+			Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		var subs []model.WebhookSubscription
+		if err := tx.Where("id IN ?", subIDs).Find(&subs).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]model.WebhookSubscription, len(subs))
+		for _, sub := range subs {
+			byID[sub.ID] = sub
+		}
+		for i := range deliveries {
+			deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id uint64, attempts int, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":       model.WebhookDeliveryDelivered,
+			"attempts":     attempts,
+			"delivered_at": at,
+			"last_error":   "",
+		}).Error
+}
+
+func (r *webhookRepository) ScheduleRetry(ctx context.Context, id uint64, attempts int, next time.Time, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":        attempts,
+			"next_attempt_at": next,
+			"last_error":      lastError,
+		}).Error
+}
+
+func (r *webhookRepository) MarkFailed(ctx context.Context, id uint64, attempts int, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":     model.WebhookDeliveryFailed,
+			"attempts":   attempts,
+			"last_error": lastError,
+		}).Error
+}
+
+func (r *webhookRepository) WithTx(tx *gorm.DB) *webhookRepository {
+	return &webhookRepository{
+		db: tx,
+	}
+}
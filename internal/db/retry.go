@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"go-rest-api/internal/config"
+)
+
+// ErrRetriesExhausted - транзакция так и не прошла из-за конфликтов
+// с параллельными транзакциями за отведённое число попыток.
+var ErrRetriesExhausted = errors.New("transaction retries exhausted")
+
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// isRetryable сообщает, что база прервала транзакцию из-за конфликта и её
+// можно безопасно выполнить заново. SQLite сообщает о блокировке, которую не
+// удалось получить за busy_timeout, кодом SQLITE_BUSY (в том числе расширенными).
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+	}
+	return false
+}
+
+// retry вызывает fn, пока она возвращает повторяемую ошибку, но не более
+// cfg.MaxAttempts раз.
+func retry(ctx context.Context, cfg config.TxRetry, fn func() error) error {
+	attempts := max(cfg.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		if attempt == attempts {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempts, err)
+		}
+
+		timer := time.NewTimer(backoff(cfg, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff - пауза после attempt неудачных попыток.
+func backoff(cfg config.TxRetry, attempt int) time.Duration {
+	delay := cfg.BaseBackoff
+	for i := 1; i < attempt && delay < cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, cfg.MaxBackoff)
+}
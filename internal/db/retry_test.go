@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"go-rest-api/internal/config"
+)
+
+var testRetry = config.TxRetry{
+	MaxAttempts: 3,
+	BaseBackoff: time.Millisecond,
+	MaxBackoff:  2 * time.Millisecond,
+}
+
+func serializationFailure() error {
+	return fmt.Errorf("commit: %w", &pgconn.PgError{Code: pgSerializationFailure})
+}
+
+func TestRetrySucceedsAfterConflict(t *testing.T) {
+	calls := 0
+	err := retry(context.Background(), testRetry, func() error {
+		calls++
+		if calls == 1 {
+			return &pgconn.PgError{Code: pgDeadlockDetected}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	err := retry(context.Background(), testRetry, func() error {
+		calls++
+		return serializationFailure()
+	})
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("err = %v, want ErrRetriesExhausted", err)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgSerializationFailure {
+		t.Fatalf("err = %v, want wrapped serialization failure", err)
+	}
+	if calls != testRetry.MaxAttempts {
+		t.Fatalf("calls = %d, want %d", calls, testRetry.MaxAttempts)
+	}
+}
+
+func TestRetrySkipsOtherErrors(t *testing.T) {
+	want := errors.New("boom")
+	calls := 0
+	err := retry(context.Background(), testRetry, func() error {
+		calls++
+		return want
+	})
+	if !errors.Is(err, want) || errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := testRetry
+	cfg.BaseBackoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+
+	err := retry(ctx, cfg, func() error {
+		cancel()
+		return serializationFailure()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := config.TxRetry{BaseBackoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{
+		1: 10 * time.Millisecond,
+		2: 20 * time.Millisecond,
+		3: 25 * time.Millisecond,
+		8: 25 * time.Millisecond,
+	} {
+		if got := backoff(cfg, attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/repository"
+)
+
+// Repositories - набор репозиториев, привязанных к одному соединению или транзакции.
+type Repositories struct {
+	Users                repository.UserRepository
+	Teams                repository.TeamRepository
+	PullRequests         repository.PullRequestRepository
+	Outbox               repository.OutboxRepository
+	Audit                repository.AuditRepository
+	ExternalPullRequests repository.ExternalPullRequestRepository
+	GitLab               repository.GitLabRepository
+}
+
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:                repository.NewUserRepository(db),
+		Teams:                repository.NewTeamRepository(db),
+		PullRequests:         repository.NewPullRequestRepository(db),
+		Outbox:               repository.NewOutboxRepository(db),
+		Audit:                repository.NewAuditRepository(db),
+		ExternalPullRequests: repository.NewExternalPullRequestRepository(db),
+		GitLab:               repository.NewGitLabRepository(db),
+	}
+}
+
+// UnitOfWork выполняет fn в одной транзакции. Все изменения должны идти через
+// переданные repos: репозитории, созданные вне fn, работают мимо транзакции.
+// Если fn вернула ошибку, транзакция откатывается, и ошибка возвращается как есть.
+// Транзакция, прерванная из-за конфликта с параллельной, может быть выполнена
+// заново, поэтому fn не должна иметь побочных эффектов вне repos.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+// TxManager повторяет транзакции, прерванные из-за конфликта, по политике
+// config.TxRetry. Когда попытки кончаются, Do возвращает ErrRetriesExhausted.
+type TxManager struct {
+	db    *gorm.DB
+	retry config.TxRetry
+}
+
+func NewTxManager(db *gorm.DB, retry config.TxRetry) *TxManager {
+	return &TxManager{
+		db:    db,
+		retry: retry,
+	}
+}
+
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	return retry(ctx, m.retry, func() error {
+		return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(ctx, NewRepositories(tx))
+		})
+	})
+}
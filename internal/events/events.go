@@ -0,0 +1,72 @@
+// Package events описывает доменные события сервиса. Сервисы записывают их в
+// outbox, доставкой получателям занимается outbox.Dispatcher.
+package events
+
+import "time"
+
+type Type string
+
+const (
+	TypePRCreated          Type = "pr.created"
+	TypeReviewerAssigned   Type = "reviewer.assigned"
+	TypeReviewerReassigned Type = "reviewer.reassigned"
+	TypePRMerged           Type = "pr.merged"
+	TypeTeamCreated        Type = "team.created"
+	TypeTeamUpdated        Type = "team.updated"
+	TypeTeamDeleted        Type = "team.deleted"
+)
+
+// Types - все события, на которые можно подписаться.
+var Types = []Type{
+	TypePRCreated,
+	TypeReviewerAssigned,
+	TypeReviewerReassigned,
+	TypePRMerged,
+	TypeTeamCreated,
+	TypeTeamUpdated,
+	TypeTeamDeleted,
+}
+
+func IsKnownType(t Type) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event - доменное событие. ID присваивается при записи в outbox и остаётся тем же
+// при повторных отправках, по нему получатели отбрасывают дубликаты.
+type Event struct {
+	ID         string    `json:"id,omitempty"`
+	Type       Type      `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+func New(t Type, data any) Event {
+	return Event{
+		Type:       t,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// ReviewerAssigned - данные события reviewer.assigned.
+type ReviewerAssigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+// ReviewerReassigned - данные события reviewer.reassigned.
+type ReviewerReassigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+}
+
+// TeamDeleted - данные события team.deleted.
+type TeamDeleted struct {
+	TeamName string `json:"team_name"`
+}
@@ -0,0 +1,121 @@
+// Package outbox доставляет доменные события, записанные сервисами в таблицу outbox.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/events"
+)
+
+// Store - часть OutboxRepository, которая нужна Dispatcher.
+type Store interface {
+	ProcessPending(ctx context.Context, now time.Time, limit int, handle func(msgs []model.OutboxMessage) error) error
+}
+
+// Dispatcher периодически забирает неотправленные сообщения и публикует их во все
+// Sink. Сообщение помечается доставленным, только если его приняли все получатели,
+// иначе повторяется позже - гарантия at-least-once. Несколько экземпляров сервиса
+// не мешают друг другу: строки блокируются через FOR UPDATE SKIP LOCKED.
+type Dispatcher struct {
+	store  Store
+	sinks  []Sink
+	cfg    config.Outbox
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewDispatcher(store Store, sinks []Sink, cfg config.Outbox, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		sinks:  sinks,
+		cfg:    cfg,
+		logger: logger,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run публикует события до отмены ctx.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil {
+			d.logger.Error("failed to dispatch outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending обрабатывает одну пачку сообщений и возвращает число доставленных.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	delivered := 0
+	err := d.store.ProcessPending(ctx, d.now(), d.cfg.BatchSize, func(msgs []model.OutboxMessage) error {
+		for i := range msgs {
+			msg := &msgs[i]
+			msg.Attempts++
+
+			publishErr := d.publish(ctx, msg)
+			if publishErr == nil {
+				now := d.now()
+				msg.DeliveredAt = &now
+				msg.LastError = ""
+				delivered++
+				continue
+			}
+
+			d.logger.Warn("outbox publish failed",
+				"id", msg.ID, "event", msg.EventType, "attempt", msg.Attempts, "error", publishErr)
+			msg.LastError = publishErr.Error()
+			msg.NextAttemptAt = d.now().Add(d.Backoff(msg.Attempts))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return delivered, nil
+}
+
+// Backoff - задержка перед следующей попыткой после attempt неудачных.
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempt && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
+
+func (d *Dispatcher) publish(ctx context.Context, msg *model.OutboxMessage) error {
+	ev := toEvent(msg)
+
+	var errs []error
+	for _, sink := range d.sinks {
+		if err := sink.Publish(ctx, ev); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func toEvent(msg *model.OutboxMessage) events.Event {
+	return events.Event{
+		ID:         strconv.FormatUint(msg.ID, 10),
+		Type:       events.Type(msg.EventType),
+		OccurredAt: msg.OccurredAt,
+		Data:       json.RawMessage(msg.Payload),
+	}
+}
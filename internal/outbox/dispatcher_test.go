@@ -0,0 +1,243 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/events"
+)
+
+// memoryStore хранит сообщения в памяти вместо таблицы outbox.
+type memoryStore struct {
+	mu   sync.Mutex
+	msgs []model.OutboxMessage
+}
+
+func newMemoryStore(msgs ...model.OutboxMessage) *memoryStore {
+	return &memoryStore{msgs: msgs}
+}
+
+func (s *memoryStore) ProcessPending(_ context.Context, now time.Time, limit int, handle func([]model.OutboxMessage) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		pending []model.OutboxMessage
+		index   []int
+	)
+	for i, msg := range s.msgs {
+		if msg.DeliveredAt == nil && !msg.NextAttemptAt.After(now) && len(pending) < limit {
+			pending = append(pending, msg)
+			index = append(index, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := handle(pending); err != nil {
+		return err
+	}
+	for i, msg := range pending {
+		s.msgs[index[i]] = msg
+	}
+	return nil
+}
+
+func (s *memoryStore) get(id uint64) model.OutboxMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range s.msgs {
+		if msg.ID == id {
+			return msg
+		}
+	}
+	return model.OutboxMessage{}
+}
+
+// flakySink не принимает первые failures событий.
+type flakySink struct {
+	failures int
+	calls    int
+}
+
+func (s *flakySink) Name() string {
+	return "flaky"
+}
+
+func (s *flakySink) Publish(context.Context, events.Event) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func testConfig() config.Outbox {
+	return config.Outbox{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   4 * time.Second,
+	}
+}
+
+func newTestDispatcher(store Store, sinks []Sink, now *time.Time) *Dispatcher {
+	d := NewDispatcher(store, sinks, testConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func pendingMessage(id uint64, eventType events.Type, payload string, at time.Time) model.OutboxMessage {
+	return model.OutboxMessage{
+		ID:            id,
+		EventType:     string(eventType),
+		Payload:       payload,
+		OccurredAt:    at,
+		NextAttemptAt: at,
+		CreatedAt:     at,
+	}
+}
+
+func TestDispatcher_PublishesToAllSinks(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore(
+		pendingMessage(1, events.TypePRCreated, `{"pull_request_id":"pr-1"}`, now),
+		pendingMessage(2, events.TypeReviewerAssigned, `{"pull_request_id":"pr-1","reviewer_id":"u2"}`, now),
+	)
+	first, second := NewMemorySink(), NewMemorySink()
+	d := newTestDispatcher(store, []Sink{first, second}, &now)
+
+	delivered, err := d.DispatchPending(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchPending: %v", err)
+	}
+	if delivered != 2 {
+		t.Fatalf("delivered = %d, want 2", delivered)
+	}
+
+	for _, sink := range []*MemorySink{first, second} {
+		got := sink.Events()
+		if len(got) != 2 {
+			t.Fatalf("sink events = %d, want 2", len(got))
+		}
+		if got[0].ID != "1" || got[0].Type != events.TypePRCreated || !got[0].OccurredAt.Equal(now) {
+			t.Errorf("events[0] = %+v", got[0])
+		}
+		var data events.ReviewerAssigned
+		if err := json.Unmarshal(got[1].Data.(json.RawMessage), &data); err != nil {
+			t.Fatalf("decode data: %v", err)
+		}
+		if got[1].ID != "2" || data.ReviewerID != "u2" {
+			t.Errorf("events[1] = %+v, data = %+v", got[1], data)
+		}
+	}
+
+	if msg := store.get(1); msg.DeliveredAt == nil || msg.Attempts != 1 {
+		t.Errorf("message 1 = %+v, want delivered after 1 attempt", msg)
+	}
+
+	delivered, err = d.DispatchPending(context.Background())
+	if err != nil || delivered != 0 {
+		t.Fatalf("second DispatchPending = %d, %v; want 0, nil", delivered, err)
+	}
+	if len(first.Events()) != 2 {
+		t.Errorf("delivered messages were published again")
+	}
+}
+
+func TestDispatcher_RetriesUntilAllSinksAccept(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore(pendingMessage(1, events.TypePRMerged, `{}`, now))
+	memory := NewMemorySink()
+	flaky := &flakySink{failures: 2}
+	d := newTestDispatcher(store, []Sink{memory, flaky}, &now)
+
+	if delivered, err := d.DispatchPending(context.Background()); err != nil || delivered != 0 {
+		t.Fatalf("DispatchPending = %d, %v; want 0, nil", delivered, err)
+	}
+	msg := store.get(1)
+	if msg.DeliveredAt != nil || msg.Attempts != 1 || msg.LastError != "flaky: unavailable" {
+		t.Fatalf("after failure = %+v", msg)
+	}
+	if want := now.Add(time.Second); !msg.NextAttemptAt.Equal(want) {
+		t.Errorf("next_attempt_at = %v, want %v", msg.NextAttemptAt, want)
+	}
+
+	// До next_attempt_at сообщение не забирается.
+	if delivered, _ := d.DispatchPending(context.Background()); delivered != 0 || flaky.calls != 1 {
+		t.Fatalf("message retried before backoff: calls = %d", flaky.calls)
+	}
+
+	now = now.Add(time.Second)
+	_, _ = d.DispatchPending(context.Background())
+	if msg := store.get(1); msg.Attempts != 2 || !msg.NextAttemptAt.Equal(now.Add(2*time.Second)) {
+		t.Fatalf("after second failure = %+v", msg)
+	}
+
+	now = now.Add(2 * time.Second)
+	if delivered, err := d.DispatchPending(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("DispatchPending = %d, %v; want 1, nil", delivered, err)
+	}
+	if msg := store.get(1); msg.DeliveredAt == nil || msg.Attempts != 3 || msg.LastError != "" {
+		t.Errorf("after success = %+v", msg)
+	}
+
+	// at-least-once: успешный получатель видит событие при каждой попытке.
+	if got := memory.Events(); len(got) != 3 || got[0].ID != got[2].ID {
+		t.Errorf("memory sink events = %+v", got)
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, testConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+	for i, w := range want {
+		if got := d.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestHTTPSink_Publish(t *testing.T) {
+	var (
+		gotID   string
+		gotBody map[string]any
+		status  = http.StatusAccepted
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get(HeaderEventID)
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, time.Second)
+	ev := toEvent(&model.OutboxMessage{ID: 7, EventType: "team.deleted", Payload: `{"team_name":"backend"}`})
+
+	if err := sink.Publish(context.Background(), ev); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if gotID != "7" || gotBody["event"] != "team.deleted" || gotBody["id"] != "7" {
+		t.Errorf("request id = %q, body = %v", gotID, gotBody)
+	}
+	if data, _ := gotBody["data"].(map[string]any); data["team_name"] != "backend" {
+		t.Errorf("data = %v", gotBody["data"])
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.Publish(context.Background(), ev); err == nil {
+		t.Error("Publish succeeded on 500 response")
+	}
+}
@@ -0,0 +1,89 @@
+// Package ratelimit ограничивает частоту запросов по алгоритму token bucket.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-rest-api/internal/config"
+)
+
+// Limit - корзина на Burst токенов, которая пополняется со скоростью Rate токенов
+// в секунду. Каждый запрос забирает один токен.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter - через сколько появится следующий токен, если запрос отклонён.
+	RetryAfter time.Duration
+	// ResetAfter - через сколько корзина снова будет полной.
+	ResetAfter time.Duration
+}
+
+// Store хранит корзины. MemoryStore подходит для одного экземпляра сервиса;
+// для нескольких экземпляров нужна реализация поверх общего хранилища.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Limiter выбирает лимит маршрута и ведёт корзины по клиентам. Маршруты с
+// собственным лимитом считаются отдельно от общего лимита клиента.
+type Limiter struct {
+	store  Store
+	def    Limit
+	routes map[string]Limit
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, cfg config.RateLimit) (*Limiter, error) {
+	def, err := toLimit(cfg.Default)
+	if err != nil {
+		return nil, fmt.Errorf("rate_limit.default: %w", err)
+	}
+
+	routes := make(map[string]Limit, len(cfg.Routes))
+	for route, rule := range cfg.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("rate_limit.routes: route %q must look like \"POST /path\"", route)
+		}
+		limit, err := toLimit(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rate_limit.routes[%s]: %w", route, err)
+		}
+		routes[strings.ToUpper(method)+" "+path] = limit
+	}
+
+	return &Limiter{
+		store:  store,
+		def:    def,
+		routes: routes,
+		now:    time.Now,
+	}, nil
+}
+
+// Allow забирает токен клиента client для маршрута method path.
+func (l *Limiter) Allow(ctx context.Context, client, method, path string) (Result, error) {
+	route := method + " " + path
+	if limit, ok := l.routes[route]; ok {
+		return l.store.Take(ctx, client+"|"+route, limit, l.now())
+	}
+	return l.store.Take(ctx, client, l.def, l.now())
+}
+
+func toLimit(rule config.RateLimitRule) (Limit, error) {
+	if rule.RPS <= 0 {
+		return Limit{}, fmt.Errorf("rps must be positive")
+	}
+	if rule.Burst < 1 {
+		return Limit{}, fmt.Errorf("burst must be at least 1")
+	}
+	return Limit{Rate: rule.RPS, Burst: rule.Burst}, nil
+}
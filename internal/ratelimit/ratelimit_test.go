@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/config"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 2, Burst: 3}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, _ := store.Take(ctx, "k", limit, now)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d = %+v", i, res)
+		}
+	}
+
+	res, _ := store.Take(ctx, "k", limit, now)
+	if res.Allowed {
+		t.Fatal("request over burst allowed")
+	}
+	if res.RetryAfter != 500*time.Millisecond || res.ResetAfter != 1500*time.Millisecond || res.Limit != 3 {
+		t.Errorf("rejected = %+v", res)
+	}
+
+	// за 0.5с при 2 токенах в секунду появляется один токен
+	now = now.Add(500 * time.Millisecond)
+	if res, _ := store.Take(ctx, "k", limit, now); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after refill = %+v", res)
+	}
+
+	if res, _ := store.Take(ctx, "other", limit, now); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("other key = %+v", res)
+	}
+
+	// корзина не наполняется больше Burst
+	now = now.Add(time.Hour)
+	if res, _ := store.Take(ctx, "k", limit, now); res.Remaining != 2 {
+		t.Errorf("after idle = %+v", res)
+	}
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 1}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	_, _ = store.Take(context.Background(), "idle", limit, now)
+	for i := 1; i < sweepEvery; i++ {
+		_, _ = store.Take(context.Background(), "busy", limit, now.Add(time.Minute))
+	}
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("full idle bucket was not swept")
+	}
+}
+
+func TestLimiter_RouteOverrides(t *testing.T) {
+	limiter, err := NewLimiter(NewMemoryStore(), config.RateLimit{
+		Default: config.RateLimitRule{RPS: 1, Burst: 2},
+		Routes: map[string]config.RateLimitRule{
+			"post /pullRequest/create": {RPS: 1, Burst: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	allow := func(client, method, path string) bool {
+		res, err := limiter.Allow(ctx, client, method, path)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		return res.Allowed
+	}
+
+	if !allow("ci", "POST", "/pullRequest/create") || allow("ci", "POST", "/pullRequest/create") {
+		t.Error("create route should allow exactly one request")
+	}
+	// отдельный лимит маршрута не расходует общий лимит клиента
+	if !allow("ci", "GET", "/pullRequest/list") || !allow("ci", "POST", "/pullRequest/close") {
+		t.Error("default limit should allow two requests")
+	}
+	if allow("ci", "GET", "/pullRequest/list") {
+		t.Error("default limit exceeded but request allowed")
+	}
+	if !allow("dev", "POST", "/pullRequest/create") {
+		t.Error("limits must be per client")
+	}
+}
+
+func TestNewLimiter_ValidatesConfig(t *testing.T) {
+	tests := map[string]config.RateLimit{
+		"zero rps":   {Default: config.RateLimitRule{RPS: 0, Burst: 1}},
+		"zero burst": {Default: config.RateLimitRule{RPS: 1, Burst: 0}},
+		"bad route": {
+			Default: config.RateLimitRule{RPS: 1, Burst: 1},
+			Routes:  map[string]config.RateLimitRule{"/pullRequest/create": {RPS: 1, Burst: 1}},
+		},
+	}
+	for name, cfg := range tests {
+		if _, err := NewLimiter(NewMemoryStore(), cfg); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
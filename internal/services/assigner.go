@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+// reviewerAssigner - общая логика выбора и замены ревьюверов.
+type reviewerAssigner struct {
+	prRepo    repository.PullRequestRepository
+	userRepo  repository.UserRepository
+	teamRepo  repository.TeamRepository
+	selectors ReviewerSelectors
+}
+
+func newReviewerAssigner(repos db.Repositories, selectors ReviewerSelectors) *reviewerAssigner {
+	return &reviewerAssigner{
+		prRepo:    repos.PullRequests,
+		userRepo:  repos.Users,
+		teamRepo:  repos.Teams,
+		selectors: selectors.withPullRequests(repos.PullRequests),
+	}
+}
+
+// replaceReviewer снимает oldReviewer с PR и назначает замену из команды PR,
+// а если она не задана - из основной команды oldReviewer.
+func (a *reviewerAssigner) replaceReviewer(ctx context.Context, pr *model.PullRequest, oldReviewer *model.User) (*model.User, error) {
+	team, err := a.reviewTeam(ctx, pr, oldReviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	newReviewer, err := a.findReplacementReviewer(ctx, pr, oldReviewer, team)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.prRepo.RemoveReviewer(ctx, pr.ID, oldReviewer.ID); err != nil {
+		return nil, err
+	}
+
+	if err := a.prRepo.AddReviewer(ctx, pr.ID, newReviewer.ID); err != nil {
+		return nil, err
+	}
+
+	return newReviewer, nil
+}
+
+// reviewTeam возвращает команду с участниками, из которой берутся ревьюверы PR.
+func (a *reviewerAssigner) reviewTeam(ctx context.Context, pr *model.PullRequest, fallback *model.User) (*model.Team, error) {
+	if pr.TeamID != nil {
+		return a.teamRepo.GetByIDWithMembers(ctx, *pr.TeamID)
+	}
+
+	primary := fallback.PrimaryTeam()
+	if primary == nil {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeNoCandidate,
+			Message: "old reviewer has no team",
+		}
+	}
+	return a.teamRepo.GetByIDWithMembers(ctx, primary.ID)
+}
+
+func (a *reviewerAssigner) selectReviewers(ctx context.Context, author *model.User, team *model.Team) ([]model.User, error) {
+	candidates := make([]model.User, 0)
+	for _, member := range team.Members {
+		if member.ID != author.ID && member.IsActive {
+			candidates = append(candidates, member)
+		}
+	}
+
+	candidates, err := a.excludeUnavailable(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := teamSettingsOrDefault(team)
+	if len(candidates) < settings.MinReviewers {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeNoCandidate,
+			Message: fmt.Sprintf("team requires at least %d active reviewers", settings.MinReviewers),
+		}
+	}
+
+	if len(candidates) == 0 {
+		return []model.User{}, nil
+	}
+
+	return a.selectors.Get(settings.ReviewerStrategy).Select(ctx, candidates, settings.MaxReviewers)
+}
+
+func (a *reviewerAssigner) findReplacementReviewer(ctx context.Context, pr *model.PullRequest, oldReviewer *model.User, team *model.Team) (*model.User, error) {
+	assignedIDs := make(map[uint]bool)
+	for _, reviewer := range pr.Reviewers {
+		assignedIDs[reviewer.ID] = true
+	}
+
+	candidates := make([]model.User, 0)
+	for _, member := range team.Members {
+		if member.ID != pr.AuthorID && // не автор
+			member.IsActive && // активен
+			member.ID != oldReviewer.ID && // не старый ревьювер
+			!assignedIDs[member.ID] { // еще не назначен
+			candidates = append(candidates, member)
+		}
+	}
+
+	candidates, err := a.excludeUnavailable(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeNoCandidate,
+			Message: "no active replacement candidate in team",
+		}
+	}
+
+	settings := teamSettingsOrDefault(team)
+	selected, err := a.selectors.Get(settings.ReviewerStrategy).Select(ctx, candidates, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeNoCandidate,
+			Message: "no active replacement candidate in team",
+		}
+	}
+
+	return &selected[0], nil
+}
+
+// excludeUnavailable убирает кандидатов, у которых сейчас идёт период отсутствия.
+func (a *reviewerAssigner) excludeUnavailable(ctx context.Context, candidates []model.User) ([]model.User, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	ids := make([]uint, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+
+	unavailable, err := a.userRepo.GetUnavailableIDs(ctx, ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]model.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !unavailable[candidate.ID] {
+			available = append(available, candidate)
+		}
+	}
+	return available, nil
+}
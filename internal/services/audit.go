@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type AuditService interface {
+	ListEntries(ctx context.Context, req dto.ListAuditRequest) (*dto.ListAuditResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+type auditCursor struct {
+	ID uint64 `json:"id"`
+}
+
+func (s *auditService) ListEntries(ctx context.Context, req dto.ListAuditRequest) (*dto.ListAuditResponse, error) {
+	q := repository.AuditQuery{
+		Filter: repository.AuditFilter{
+			EntityType: model.AuditEntityType(req.EntityType),
+			EntityID:   req.EntityID,
+			Actor:      req.Actor,
+			From:       req.From,
+			To:         req.To,
+		},
+		Limit: req.Limit,
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultListLimit
+	}
+
+	if req.Cursor != "" {
+		var cursor auditCursor
+		if err := decodeCursor(req.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		q.BeforeID = cursor.ID
+	}
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	limit := q.Limit
+	q.Limit++
+
+	entries, err := s.auditRepo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ListAuditResponse{
+		Entries: make([]dto.AuditEntry, 0, min(len(entries), limit)),
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		result.NextCursor, err = encodeCursor(auditCursor{ID: entries[len(entries)-1].ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range entries {
+		entry, err := mapAuditEntryToDTO(&entries[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+// auditRecord описывает одну операцию. before и after - состояние сущности до и
+// после неё (nil, если сущности не было или она удалена).
+type auditRecord struct {
+	operation  string
+	entityType model.AuditEntityType
+	entityID   string
+	targetIDs  []string
+	before     any
+	after      any
+}
+
+// recordAudit пишет rec в audit_log через auditRepo. Сервисы вызывают его внутри
+// uow.Do с репозиторием транзакции, как и outbox: запись журнала фиксируется или
+// откатывается вместе с самим изменением. Операция, не изменившая сущность
+// (например, повторный merge), в журнал не попадает.
+func recordAudit(ctx context.Context, auditRepo repository.AuditRepository, rec auditRecord) error {
+	diff, err := auditDiff(rec.before, rec.after)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	return auditRepo.Create(ctx, &model.AuditEntry{
+		OccurredAt: timeNow(),
+		Actor:      auth.ActorFromContext(ctx).ID,
+		Operation:  rec.operation,
+		EntityType: rec.entityType,
+		EntityID:   rec.entityID,
+		TargetIDs:  uniqueTargets(rec.entityID, rec.targetIDs),
+		Diff:       string(data),
+	})
+}
+
+// auditDiff сравнивает JSON-представления before и after по полям верхнего уровня.
+func auditDiff(before, after any) (map[string]dto.AuditChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]dto.AuditChange)
+	for key, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[key]) {
+			diff[key] = dto.AuditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			diff[key] = dto.AuditChange{Before: nil, After: value}
+		}
+	}
+
+	return diff, nil
+}
+
+func jsonFields(v any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// uniqueTargets убирает пустые значения, дубликаты и саму сущность.
+func uniqueTargets(entityID string, ids []string) model.TargetIDs {
+	seen := map[string]bool{entityID: true}
+	targets := make(model.TargetIDs, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		targets = append(targets, id)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func mapAuditEntryToDTO(entry *model.AuditEntry) (dto.AuditEntry, error) {
+	diff := make(map[string]dto.AuditChange)
+	if err := json.Unmarshal([]byte(entry.Diff), &diff); err != nil {
+		return dto.AuditEntry{}, err
+	}
+
+	targets := []string(entry.TargetIDs)
+	if targets == nil {
+		targets = []string{}
+	}
+
+	return dto.AuditEntry{
+		ID:         entry.ID,
+		OccurredAt: entry.OccurredAt,
+		Actor:      entry.Actor,
+		Operation:  entry.Operation,
+		EntityType: string(entry.EntityType),
+		EntityID:   entry.EntityID,
+		TargetIDs:  targets,
+		Diff:       diff,
+	}, nil
+}
+
+func reassignmentTargets(reports []dto.PullRequestReassignmentReport) []string {
+	ids := make([]string, 0, len(reports))
+	for _, report := range reports {
+		ids = append(ids, report.PullRequestID)
+		for _, replacement := range report.Replacements {
+			ids = append(ids, replacement.NewUserID)
+		}
+	}
+	return ids
+}
+
+func teamMemberIDs(members []dto.TeamMember) []string {
+	ids := make([]string, len(members))
+	for i, member := range members {
+		ids[i] = member.UserID
+	}
+	return ids
+}
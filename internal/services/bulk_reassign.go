@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
+)
+
+// bulkReassignment - план массового переназначения ревью уходящих пользователей
+// на оставшихся участников команды.
+type bulkReassignment struct {
+	moves   []repository.ReviewerMove
+	reports []dto.PullRequestReassignmentReport
+}
+
+// reassignedEvents возвращает reviewer.reassigned на каждую замену из плана.
+func (p *bulkReassignment) reassignedEvents() []events.Event {
+	evs := make([]events.Event, 0, len(p.moves))
+	for _, move := range p.moves {
+		evs = append(evs, events.New(events.TypeReviewerReassigned, events.ReviewerReassigned{
+			PullRequestID: fmt.Sprintf("pr-%d", move.PrID),
+			OldReviewerID: fmt.Sprintf("u%d", move.OldReviewerID),
+			NewReviewerID: fmt.Sprintf("u%d", move.NewReviewerID),
+		}))
+	}
+	return evs
+}
+
+// listLeavingAssignments загружает назначения на OPEN PR, где ревьювером указан
+// кто-то из leaving.
+func (a *reviewerAssigner) listLeavingAssignments(ctx context.Context, leaving map[uint]bool) ([]repository.ReviewAssignment, error) {
+	if len(leaving) == 0 {
+		return nil, nil
+	}
+
+	leavingIDs := make([]uint, 0, len(leaving))
+	for id := range leaving {
+		leavingIDs = append(leavingIDs, id)
+	}
+	return a.prRepo.ListOpenAssignments(ctx, leavingIDs)
+}
+
+// lockLeavingAssignments блокирует OPEN PR, где ревьювером указан кто-то из
+// leaving, в порядке id и возвращает их назначения, прочитанные после получения
+// блокировок. Так массовая замена не пересекается с ReassignReviewer, MergePR и
+// SubmitReview на тех же PR.
+func (a *reviewerAssigner) lockLeavingAssignments(ctx context.Context, leaving map[uint]bool) ([]repository.ReviewAssignment, error) {
+	locked := make(map[uint]bool)
+	for {
+		assignments, err := a.listLeavingAssignments(ctx, leaving)
+		if err != nil {
+			return nil, err
+		}
+
+		// до получения блокировок назначения могли измениться, поэтому список
+		// перечитывается, пока в нём не останется незаблокированных PR
+		pending := make([]uint, 0)
+		for _, prID := range assignmentPRIDs(assignments) {
+			if !locked[prID] {
+				pending = append(pending, prID)
+			}
+		}
+		if len(pending) == 0 {
+			return assignments, nil
+		}
+
+		for _, prID := range pending {
+			if _, err := a.prRepo.GetByIDForUpdate(ctx, prID); err != nil {
+				return nil, err
+			}
+			locked[prID] = true
+		}
+	}
+}
+
+// planBulkReassignment снимает leaving с их OPEN ревью из assignments и распределяет
+// эти ревью между активными участниками команды. Всё считается в памяти по заранее
+// загруженным данным, поэтому число запросов к БД не зависит от количества PR.
+func (a *reviewerAssigner) planBulkReassignment(
+	ctx context.Context,
+	team *model.Team,
+	leaving map[uint]bool,
+	assignments []repository.ReviewAssignment,
+) (*bulkReassignment, error) {
+	plan := &bulkReassignment{
+		moves:   []repository.ReviewerMove{},
+		reports: []dto.PullRequestReassignmentReport{},
+	}
+	if len(assignments) == 0 {
+		return plan, nil
+	}
+
+	candidates := make([]model.User, 0, len(team.Members))
+	for _, member := range team.Members {
+		if member.IsActive && !leaving[member.ID] {
+			candidates = append(candidates, member)
+		}
+	}
+
+	candidates, err := a.excludeUnavailable(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	load := map[uint]int64{}
+	if len(candidates) > 0 {
+		ids := make([]uint, len(candidates))
+		for i, candidate := range candidates {
+			ids[i] = candidate.ID
+		}
+		load, err = a.prRepo.CountOpenReviews(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	settings := teamSettingsOrDefault(team)
+	candidates = shuffleUsers(candidates)
+
+	type prAssignments struct {
+		authorID  uint
+		reviewers []uint
+	}
+	byPR := make(map[uint]*prAssignments)
+	prIDs := make([]uint, 0)
+	for _, assignment := range assignments {
+		entry, ok := byPR[assignment.PrID]
+		if !ok {
+			entry = &prAssignments{authorID: assignment.AuthorID}
+			byPR[assignment.PrID] = entry
+			prIDs = append(prIDs, assignment.PrID)
+		}
+		entry.reviewers = append(entry.reviewers, assignment.ReviewerID)
+	}
+	sort.Slice(prIDs, func(i, j int) bool { return prIDs[i] < prIDs[j] })
+
+	for _, prID := range prIDs {
+		entry := byPR[prID]
+		assigned := make(map[uint]bool, len(entry.reviewers))
+		for _, reviewerID := range entry.reviewers {
+			assigned[reviewerID] = true
+		}
+
+		report := dto.PullRequestReassignmentReport{
+			PullRequestID: fmt.Sprintf("pr-%d", prID),
+			Replacements:  []dto.ReviewerReplacement{},
+			NoCandidate:   []string{},
+		}
+
+		final := make([]uint, 0, len(entry.reviewers))
+		for _, reviewerID := range entry.reviewers {
+			if !leaving[reviewerID] {
+				final = append(final, reviewerID)
+				continue
+			}
+
+			eligible := make([]model.User, 0, len(candidates))
+			for _, candidate := range candidates {
+				if candidate.ID != entry.authorID && !assigned[candidate.ID] {
+					eligible = append(eligible, candidate)
+				}
+			}
+
+			if len(eligible) == 0 {
+				report.NoCandidate = append(report.NoCandidate, fmt.Sprintf("u%d", reviewerID))
+				final = append(final, reviewerID)
+				continue
+			}
+
+			chosen := pickLeastLoaded(eligible, load)
+			if settings.ReviewerStrategy == ReviewerStrategyRandom {
+				chosen = eligible[rand.Intn(len(eligible))]
+			}
+
+			load[chosen.ID]++
+			assigned[chosen.ID] = true
+			final = append(final, chosen.ID)
+
+			plan.moves = append(plan.moves, repository.ReviewerMove{
+				PrID:          prID,
+				OldReviewerID: reviewerID,
+				NewReviewerID: chosen.ID,
+			})
+			report.Replacements = append(report.Replacements, dto.ReviewerReplacement{
+				OldUserID: fmt.Sprintf("u%d", reviewerID),
+				NewUserID: fmt.Sprintf("u%d", chosen.ID),
+			})
+		}
+
+		report.AssignedReviewers = make([]string, len(final))
+		for i, reviewerID := range final {
+			report.AssignedReviewers[i] = fmt.Sprintf("u%d", reviewerID)
+		}
+		plan.reports = append(plan.reports, report)
+	}
+
+	return plan, nil
+}
+
+// pickLeastLoaded возвращает первого кандидата с минимальной нагрузкой; кандидаты
+// уже перемешаны, так что при равенстве выбор случайный.
+func pickLeastLoaded(candidates []model.User, load map[uint]int64) model.User {
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if load[candidate.ID] < load[best.ID] {
+			best = candidate
+		}
+	}
+	return best
+}
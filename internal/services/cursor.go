@@ -0,0 +1,30 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"go-rest-api/internal/api/dto"
+)
+
+func encodeCursor(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return &ServiceError{
+			Code:    dto.ErrorCodeInvalidCursor,
+			Message: "invalid cursor",
+		}
+	}
+	return nil
+}
@@ -1,6 +1,11 @@
 package services
 
-import "go-rest-api/internal/api/dto"
+import (
+	"errors"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+)
 
 type ServiceError struct {
 	Code    dto.ErrorCode
@@ -10,3 +15,15 @@ type ServiceError struct {
 func (e *ServiceError) Error() string {
 	return e.Message
 }
+
+// conflictError превращает исчерпание повторов транзакции в CONCURRENT_UPDATE,
+// остальные ошибки возвращает как есть.
+func conflictError(err error) error {
+	if errors.Is(err, db.ErrRetriesExhausted) {
+		return &ServiceError{
+			Code:    dto.ErrorCodeConcurrentUpdate,
+			Message: "PR is being modified concurrently, retry the request",
+		}
+	}
+	return err
+}
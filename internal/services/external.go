@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+// errExternalPRLinked - внешний PR уже связан с PR сервиса. Транзакция
+// откатывается, а событие считается повторной доставкой.
+var errExternalPRLinked = errors.New("external pull request already linked")
+
+// openExternalPR создаёт PR сервиса для внешнего PR и запоминает связь.
+// Вызывается внутри uow.Do на привязанном сервисе.
+func openExternalPR(
+	ctx context.Context,
+	prs *pullRequestService,
+	links repository.ExternalPullRequestRepository,
+	link model.ExternalPullRequest,
+	authorID uint,
+	title string,
+) (*dto.PullRequest, error) {
+	_, err := links.Get(ctx, link.Provider, link.Repository, link.Number)
+	if err == nil {
+		return nil, errExternalPRLinked
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	pr, err := prs.createPR(ctx, 0, authorID, title, "")
+	if err != nil {
+		return nil, err
+	}
+
+	link.PrID, err = parsePRID(pr.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := links.Create(ctx, &link); err != nil {
+		// Параллельная доставка того же события успела создать связь.
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, errExternalPRLinked
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+// findExternalPR возвращает id PR сервиса, связанного с внешним PR.
+func findExternalPR(
+	ctx context.Context,
+	links repository.ExternalPullRequestRepository,
+	provider model.ExternalProvider,
+	repo string,
+	number uint64,
+) (uint, error) {
+	link, err := links.Get(ctx, provider, repo, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "PR not found",
+			}
+		}
+		return 0, err
+	}
+	return link.PrID, nil
+}
+
+// rejectedEvent превращает отказ сервиса выполнить действие события (PR не
+// связан, уже слит, переход невозможен) в ответ ignored. Внешняя система
+// повторяет доставки, на которые получила ошибку, и со временем отключает
+// вебхук, а повтор таких событий ничего не изменит. CONCURRENT_UPDATE остаётся
+// ошибкой: повторная доставка может пройти.
+func rejectedEvent(err error) (*dto.WebhookResponse, bool) {
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Code == dto.ErrorCodeConcurrentUpdate {
+		return nil, false
+	}
+	return ignored(serviceErr.Message), true
+}
+
+func ignored(reason string) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		Status: dto.WebhookStatusIgnored,
+		Reason: reason,
+	}
+}
+
+func processed(pr *dto.PullRequest) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		Status:      dto.WebhookStatusProcessed,
+		PullRequest: pr,
+	}
+}
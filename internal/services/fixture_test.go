@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/repository/memory"
+	"go-rest-api/internal/services"
+)
+
+// adminContext - контекст запроса администратора: тесты, не проверяющие права,
+// выполняют операции от его имени.
+func adminContext() context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{ID: "admin", Role: auth.RoleAdmin})
+}
+
+// userContext - контекст запроса пользователя userID.
+func userContext(userID string) context.Context {
+	return auth.WithActor(context.Background(), auth.Actor{ID: userID, Role: auth.RoleUser})
+}
+
+// fixture - сервисы поверх базы в памяти.
+type fixture struct {
+	store *memory.Store
+	repos db.Repositories
+	teams services.TeamService
+	users services.UserService
+	prs   services.PullRequestService
+}
+
+func newFixture() *fixture {
+	store := memory.NewStore()
+	return newFixtureWithUoW(store, store)
+}
+
+// newFixtureWithUoW строит сервисы, выполняющие транзакции через uow, поверх store.
+func newFixtureWithUoW(store *memory.Store, uow db.UnitOfWork) *fixture {
+	repos := store.Repositories()
+	selectors := services.NewReviewerSelectors(repos.PullRequests)
+	return &fixture{
+		store: store,
+		repos: repos,
+		teams: services.NewTeamService(uow, repos, selectors),
+		users: services.NewUserService(uow, repos, selectors),
+		prs:   services.NewPullRequestService(uow, repos, selectors),
+	}
+}
+
+// createTeam создаёт команду из активных пользователей с указанными id.
+func (f *fixture) createTeam(t *testing.T, name string, userIDs ...string) {
+	t.Helper()
+
+	members := make([]dto.TeamMember, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = dto.TeamMember{UserID: userID, Username: "user-" + userID, IsActive: true}
+	}
+	_, err := f.teams.CreateTeam(adminContext(), dto.CreateTeamRequest{
+		TeamName: name,
+		Members:  members,
+	})
+	mustSucceed(t, err)
+}
+
+func (f *fixture) updateSettings(t *testing.T, req dto.UpdateTeamSettingsRequest) {
+	t.Helper()
+
+	_, err := f.teams.UpdateSettings(adminContext(), req)
+	mustSucceed(t, err)
+}
+
+func (f *fixture) createPR(t *testing.T, prID, authorID string) *dto.PullRequest {
+	t.Helper()
+
+	pr, err := f.prs.CreatePR(adminContext(), dto.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: "PR " + prID,
+		AuthorID:        authorID,
+	})
+	mustSucceed(t, err)
+	return pr
+}
+
+func (f *fixture) mergePR(t *testing.T, prID string) {
+	t.Helper()
+
+	_, err := f.prs.MergePR(adminContext(), dto.MergePRRequest{PullRequestID: prID})
+	mustSucceed(t, err)
+}
+
+func (f *fixture) closePR(t *testing.T, prID string) {
+	t.Helper()
+
+	_, err := f.prs.ClosePR(adminContext(), dto.ClosePRRequest{PullRequestID: prID})
+	mustSucceed(t, err)
+}
+
+func mustSucceed(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+}
+
+// assertCode проверяет, что err - ServiceError с кодом want, а при пустом want -
+// что ошибки нет.
+func assertCode(t *testing.T, err error, want dto.ErrorCode) {
+	t.Helper()
+
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	var serviceErr *services.ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("err = %v, want ServiceError %s", err, want)
+	}
+	if serviceErr.Code != want {
+		t.Fatalf("code = %s (%s), want %s", serviceErr.Code, serviceErr.Message, want)
+	}
+}
+
+// conflictingUoW имитирует транзакции, которые не удалось выполнить из-за
+// конфликтов с параллельными.
+type conflictingUoW struct{}
+
+func (conflictingUoW) Do(context.Context, func(context.Context, db.Repositories) error) error {
+	return fmt.Errorf("%w after 3 attempts", db.ErrRetriesExhausted)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+)
+
+type GitHubService interface {
+	HandlePullRequestEvent(ctx context.Context, event dto.GitHubPullRequestEvent) (*dto.WebhookResponse, error)
+}
+
+// githubService переводит события GitHub в операции над PR. PR GitHub
+// связывается с PR сервиса по (full_name репозитория, номер): номера PR
+// повторяются в разных репозиториях.
+type githubService struct {
+	uow       db.UnitOfWork
+	prService *pullRequestService
+	users     map[string]string
+}
+
+func NewGitHubService(
+	uow db.UnitOfWork,
+	repos db.Repositories,
+	selectors ReviewerSelectors,
+	users map[string]string,
+) GitHubService {
+	return &githubService{
+		uow:       uow,
+		prService: newPullRequestService(uow, repos, selectors),
+		users:     users,
+	}
+}
+
+func (s *githubService) HandlePullRequestEvent(ctx context.Context, event dto.GitHubPullRequestEvent) (*dto.WebhookResponse, error) {
+	var authorID uint
+	switch event.Action {
+	case "opened":
+		userID, ok := s.users[event.PullRequest.User.Login]
+		if !ok {
+			return ignored(fmt.Sprintf("unknown GitHub login: %s", event.PullRequest.User.Login)), nil
+		}
+		var err error
+		authorID, err = parseUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+	case "closed", "reopened":
+	default:
+		return ignored(fmt.Sprintf("unsupported action: %s", event.Action)), nil
+	}
+
+	link := model.ExternalPullRequest{
+		Provider:   model.ExternalProviderGitHub,
+		Repository: event.Repository.FullName,
+		Number:     uint64(event.PullRequest.Number),
+	}
+
+	var pr *dto.PullRequest
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		prs := s.prService.bind(repos)
+
+		if event.Action == "opened" {
+			var err error
+			pr, err = openExternalPR(ctx, prs, repos.ExternalPullRequests, link, authorID, event.PullRequest.Title)
+			return err
+		}
+
+		prID, err := findExternalPR(ctx, repos.ExternalPullRequests, link.Provider, link.Repository, link.Number)
+		if err != nil {
+			return err
+		}
+
+		to := model.PrStatusOpen
+		if event.Action == "closed" {
+			to = model.PrStatusClosed
+			if event.PullRequest.Merged {
+				to = model.PrStatusMerged
+			}
+		}
+		locked, err := prs.getForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		// GitHub сообщает о свершившемся факте, поэтому проверка одобрений
+		// сервиса не может его отменить.
+		pr, err = prs.transition(ctx, locked, to, true)
+		return err
+	})
+
+	if errors.Is(err, errExternalPRLinked) {
+		return ignored("pull request already exists"), nil
+	}
+	if err != nil {
+		err = conflictError(err)
+		if resp, ok := rejectedEvent(err); ok {
+			return resp, nil
+		}
+		return nil, err
+	}
+
+	return processed(pr), nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type GitLabService interface {
+	HandleMergeRequestEvent(ctx context.Context, eventUUID string, event dto.GitLabMergeRequestEvent) (*dto.WebhookResponse, error)
+	GetUsers(ctx context.Context) ([]dto.GitLabUserMapping, error)
+	SaveUser(ctx context.Context, req dto.GitLabUserMapping) (*dto.GitLabUserMapping, error)
+	DeleteUser(ctx context.Context, username string) error
+}
+
+// gitlabService переводит события Merge Request Hook в операции над PR. MR
+// связывается с PR сервиса по (id проекта, iid): iid уникален только внутри
+// проекта. Обработанные события запоминаются по X-Gitlab-Event-UUID в той же
+// транзакции, что и изменение PR, повторная доставка игнорируется.
+type gitlabService struct {
+	uow        db.UnitOfWork
+	prService  *pullRequestService
+	userRepo   repository.UserRepository
+	gitlabRepo repository.GitLabRepository
+}
+
+func NewGitLabService(uow db.UnitOfWork, repos db.Repositories, selectors ReviewerSelectors) GitLabService {
+	return &gitlabService{
+		uow:        uow,
+		prService:  newPullRequestService(uow, repos, selectors),
+		userRepo:   repos.Users,
+		gitlabRepo: repos.GitLab,
+	}
+}
+
+// gitlabStatuses - статус PR после действия над MR.
+var gitlabStatuses = map[string]model.PrStatus{
+	"merge":  model.PrStatusMerged,
+	"close":  model.PrStatusClosed,
+	"reopen": model.PrStatusOpen,
+}
+
+// errEventProcessed - событие с этим X-Gitlab-Event-UUID уже обработано.
+var errEventProcessed = errors.New("gitlab event already processed")
+
+func (s *gitlabService) HandleMergeRequestEvent(ctx context.Context, eventUUID string, event dto.GitLabMergeRequestEvent) (*dto.WebhookResponse, error) {
+	attrs := event.ObjectAttributes
+
+	var authorID uint
+	switch attrs.Action {
+	case "open":
+		mapping, err := s.gitlabRepo.GetUser(ctx, event.User.Username)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ignored(fmt.Sprintf("unknown GitLab username: %s", event.User.Username)), nil
+			}
+			return nil, err
+		}
+		authorID = mapping.UserID
+	case "update", "merge", "close", "reopen":
+	default:
+		return ignored(fmt.Sprintf("unsupported action: %s", attrs.Action)), nil
+	}
+
+	link := model.ExternalPullRequest{
+		Provider:   model.ExternalProviderGitLab,
+		Repository: strconv.FormatUint(event.Project.ID, 10),
+		Number:     uint64(attrs.IID),
+	}
+
+	var pr *dto.PullRequest
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		// Параллельная повторная доставка упрётся в первичный ключ события и
+		// откатит свою транзакцию целиком.
+		if eventUUID != "" {
+			if err := repos.GitLab.RecordEvent(ctx, eventUUID, timeNow()); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return errEventProcessed
+				}
+				return err
+			}
+		}
+
+		prs := s.prService.bind(repos)
+
+		if attrs.Action == "open" {
+			var err error
+			pr, err = openExternalPR(ctx, prs, repos.ExternalPullRequests, link, authorID, attrs.Title)
+			return err
+		}
+
+		prID, err := findExternalPR(ctx, repos.ExternalPullRequests, link.Provider, link.Repository, link.Number)
+		if err != nil {
+			return err
+		}
+
+		if attrs.Action == "update" {
+			pr, err = prs.updateTitle(ctx, prID, attrs.Title)
+			return err
+		}
+
+		locked, err := prs.getForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		// Действие уже выполнено в GitLab, проверка одобрений сервиса его не отменит.
+		pr, err = prs.transition(ctx, locked, gitlabStatuses[attrs.Action], true)
+		return err
+	})
+
+	switch {
+	case errors.Is(err, errEventProcessed):
+		return ignored("event already processed"), nil
+	case errors.Is(err, errExternalPRLinked):
+		return ignored("pull request already exists"), nil
+	case err != nil:
+		err = conflictError(err)
+		if resp, ok := rejectedEvent(err); ok {
+			return resp, nil
+		}
+		return nil, err
+	}
+
+	return processed(pr), nil
+}
+
+func (s *gitlabService) GetUsers(ctx context.Context) ([]dto.GitLabUserMapping, error) {
+	users, err := s.gitlabRepo.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.GitLabUserMapping, len(users))
+	for i, user := range users {
+		result[i] = mapGitLabUserToDTO(&user)
+	}
+	return result, nil
+}
+
+func (s *gitlabService) SaveUser(ctx context.Context, req dto.GitLabUserMapping) (*dto.GitLabUserMapping, error) {
+	userID, err := parseUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "user not found",
+			}
+		}
+		return nil, err
+	}
+
+	user := &model.GitLabUser{
+		Username: req.Username,
+		UserID:   userID,
+	}
+	if err := s.gitlabRepo.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	result := mapGitLabUserToDTO(user)
+	return &result, nil
+}
+
+func (s *gitlabService) DeleteUser(ctx context.Context, username string) error {
+	deleted, err := s.gitlabRepo.DeleteUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    dto.ErrorCodeNotFound,
+			Message: "GitLab user mapping not found",
+		}
+	}
+	return nil
+}
+
+func mapGitLabUserToDTO(user *model.GitLabUser) dto.GitLabUserMapping {
+	return dto.GitLabUserMapping{
+		Username: user.Username,
+		UserID:   fmt.Sprintf("u%d", user.UserID),
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db/model"
+)
+
+// prTransitions - допустимые переходы статуса PR.
+var prTransitions = map[model.PrStatus][]model.PrStatus{
+	model.PrStatusOpen:   {model.PrStatusMerged, model.PrStatusClosed},
+	model.PrStatusClosed: {model.PrStatusOpen},
+}
+
+func canTransitionPR(from, to model.PrStatus) bool {
+	for _, allowed := range prTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionPR переводит PR в статус to. Переход в текущий статус ничего не меняет,
+// поэтому повторный merge сохраняет исходный merged_at.
+func transitionPR(pr *model.PullRequest, to model.PrStatus, now time.Time) (bool, error) {
+	if pr.Status == to {
+		return false, nil
+	}
+
+	if !canTransitionPR(pr.Status, to) {
+		return false, &ServiceError{
+			Code:    dto.ErrorCodeInvalidTransition,
+			Message: fmt.Sprintf("cannot change PR status from %s to %s", pr.Status, to),
+		}
+	}
+
+	pr.Status = to
+	pr.UpdatedAt = now
+	if to == model.PrStatusMerged {
+		pr.MergedAt = &now
+	}
+	return true, nil
+}
@@ -4,40 +4,72 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 
 	"gorm.io/gorm"
 
 	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/auth"
+	"go-rest-api/internal/db"
 	"go-rest-api/internal/db/model"
 	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
 )
 
 type PullRequestService interface {
 	CreatePR(ctx context.Context, req dto.CreatePRRequest) (*dto.PullRequest, error)
 	MergePR(ctx context.Context, req dto.MergePRRequest) (*dto.PullRequest, error)
+	ClosePR(ctx context.Context, req dto.ClosePRRequest) (*dto.PullRequest, error)
+	ReopenPR(ctx context.Context, req dto.ReopenPRRequest) (*dto.PullRequest, error)
+	UpdatePR(ctx context.Context, req dto.UpdatePRRequest) (*dto.PullRequest, error)
+	SubmitReview(ctx context.Context, req dto.SubmitReviewRequest) (*dto.PullRequest, error)
 	ReassignReviewer(ctx context.Context, req dto.ReassignPRRequest) (*dto.ReassignPRResponse, error)
+	ListPRs(ctx context.Context, req dto.ListPRsRequest) (*dto.ListPRsResponse, error)
 }
 
 type pullRequestService struct {
-	db       *gorm.DB
-	prRepo   repository.PullRequestRepository
-	userRepo repository.UserRepository
-	teamRepo repository.TeamRepository
+	uow        db.UnitOfWork
+	prRepo     repository.PullRequestRepository
+	userRepo   repository.UserRepository
+	teamRepo   repository.TeamRepository
+	outboxRepo repository.OutboxRepository
+	auditRepo  repository.AuditRepository
+	selectors  ReviewerSelectors
+	assigner   *reviewerAssigner
 }
 
-func NewPullRequestService(
-	db *gorm.DB,
-	prRepo repository.PullRequestRepository,
-	userRepo repository.UserRepository,
-	teamRepo repository.TeamRepository,
-) PullRequestService {
+func NewPullRequestService(uow db.UnitOfWork, repos db.Repositories, selectors ReviewerSelectors) PullRequestService {
+	return newPullRequestService(uow, repos, selectors)
+}
+
+// newPullRequestService нужен вебхукам: они вызывают операции PR внутри
+// собственной транзакции через bind.
+func newPullRequestService(uow db.UnitOfWork, repos db.Repositories, selectors ReviewerSelectors) *pullRequestService {
 	return &pullRequestService{
-		db:       db,
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+		uow:        uow,
+		prRepo:     repos.PullRequests,
+		userRepo:   repos.Users,
+		teamRepo:   repos.Teams,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  selectors,
+		assigner:   newReviewerAssigner(repos, selectors),
+	}
+}
+
+// bind возвращает копию сервиса, работающую через репозитории транзакции.
+// Внутри uow.Do сервис перекрывается этой копией, чтобы ни один запрос
+// не ушёл мимо транзакции.
+func (s *pullRequestService) bind(repos db.Repositories) *pullRequestService {
+	return &pullRequestService{
+		uow:        s.uow,
+		prRepo:     repos.PullRequests,
+		userRepo:   repos.Users,
+		teamRepo:   repos.Teams,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  s.selectors,
+		assigner:   newReviewerAssigner(repos, s.selectors),
 	}
 }
 
@@ -52,98 +84,341 @@ func (s *pullRequestService) CreatePR(ctx context.Context, req dto.CreatePRReque
 		return nil, err
 	}
 
+	// от автора зависит, из какой команды назначаются ревьюверы
+	if actor := auth.ActorFromContext(ctx); !actor.IsAdmin() && actor.ID != fmt.Sprintf("u%d", authorID) {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeForbidden,
+			Message: "cannot create a PR on behalf of another user",
+		}
+	}
+
 	var result *dto.PullRequest
 
-	err = s.db.Transaction(func(tx *gorm.DB) error {
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		result, err = s.bind(repos).createPR(ctx, prID, authorID, req.PullRequestName, req.TeamName)
+		return err
+	})
+
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return result, nil
+}
+
+// createPR создаёт PR и назначает ревьюверов. Вызывается внутри uow.Do на
+// привязанном сервисе. При prID == 0 PR получает следующий свободный id.
+func (s *pullRequestService) createPR(ctx context.Context, prID, authorID uint, title, teamName string) (*dto.PullRequest, error) {
+	if prID != 0 {
 		exists, err := s.prRepo.ExistsByID(ctx, prID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if exists {
-			return &ServiceError{
+			return nil, &ServiceError{
 				Code:    dto.ErrorCodePRExists,
 				Message: "PR id already exists",
 			}
 		}
+	}
 
-		author, err := s.userRepo.GetByIDWithTeams(ctx, authorID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return &ServiceError{
-					Code:    dto.ErrorCodeNotFound,
-					Message: "author not found",
-				}
+	author, err := s.userRepo.GetByIDWithTeams(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "author not found",
 			}
-			return err
 		}
+		return nil, err
+	}
 
-		if len(author.Teams) == 0 {
-			return &ServiceError{
-				Code:    dto.ErrorCodeNotFound,
-				Message: "author has no team",
+	teamID, err := resolveReviewTeam(author, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetByIDWithMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.assigner.selectReviewers(ctx, author, team)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timeNow()
+	pr := &model.PullRequest{
+		ID:        prID,
+		Title:     title,
+		AuthorID:  authorID,
+		TeamID:    &team.ID,
+		Status:    model.PrStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.prRepo.Create(ctx, pr); err != nil {
+		// Параллельный CreatePR с тем же id успел вставить PR после нашей проверки.
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodePRExists,
+				Message: "PR id already exists",
 			}
 		}
+		return nil, err
+	}
 
-		team, err := s.teamRepo.GetByNameWithMembers(ctx, author.Teams[0].Name)
-		if err != nil {
-			return err
+	for _, reviewer := range reviewers {
+		if err := s.prRepo.AddReviewer(ctx, pr.ID, reviewer.ID); err != nil {
+			return nil, err
 		}
+		pr.Reviews = append(pr.Reviews, model.PullRequestReviewer{
+			PrID:       pr.ID,
+			ReviewerID: reviewer.ID,
+			State:      model.ReviewStatePending,
+		})
+	}
+	pr.Reviewers = reviewers
+	pr.Team = team
 
-		pr := &model.PullRequest{
-			Title:    req.PullRequestName,
-			AuthorID: authorID,
-			Status:   model.PrStatusOpen,
-		}
-		if prID != 0 {
-			pr.ID = prID
+	mapped := mapPullRequestToDTO(pr)
+
+	published := []events.Event{events.New(events.TypePRCreated, mapped)}
+	for _, reviewerID := range mapped.AssignedReviewers {
+		published = append(published, events.New(events.TypeReviewerAssigned, events.ReviewerAssigned{
+			PullRequestID: mapped.PullRequestID,
+			ReviewerID:    reviewerID,
+		}))
+	}
+	if err := s.outboxRepo.Enqueue(ctx, published...); err != nil {
+		return nil, err
+	}
+
+	err = recordAudit(ctx, s.auditRepo, auditRecord{
+		operation:  "pr.create",
+		entityType: model.AuditEntityPullRequest,
+		entityID:   mapped.PullRequestID,
+		targetIDs:  append([]string{mapped.AuthorID}, mapped.AssignedReviewers...),
+		after:      &mapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mapped, nil
+}
+
+func (s *pullRequestService) MergePR(ctx context.Context, req dto.MergePRRequest) (*dto.PullRequest, error) {
+	return s.changeStatus(ctx, req.PullRequestID, model.PrStatusMerged)
+}
+
+func (s *pullRequestService) ClosePR(ctx context.Context, req dto.ClosePRRequest) (*dto.PullRequest, error) {
+	return s.changeStatus(ctx, req.PullRequestID, model.PrStatusClosed)
+}
+
+func (s *pullRequestService) ReopenPR(ctx context.Context, req dto.ReopenPRRequest) (*dto.PullRequest, error) {
+	return s.changeStatus(ctx, req.PullRequestID, model.PrStatusOpen)
+}
+
+// UpdatePR меняет название PR. Слитый PR не изменяется.
+func (s *pullRequestService) UpdatePR(ctx context.Context, req dto.UpdatePRRequest) (*dto.PullRequest, error) {
+	prID, err := parsePRID(req.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dto.PullRequest
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		result, err = s.bind(repos).updateTitle(ctx, prID, req.PullRequestName)
+		return err
+	})
+
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return result, nil
+}
+
+// updateTitle меняет название PR внутри uow.Do.
+func (s *pullRequestService) updateTitle(ctx context.Context, prID uint, title string) (*dto.PullRequest, error) {
+	pr, err := s.getForUpdate(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status == model.PrStatusMerged {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodePRMerged,
+			Message: "cannot update merged PR",
 		}
-		if err := s.prRepo.Create(ctx, pr); err != nil {
-			return err
+	}
+
+	before := mapPullRequestToDTO(pr)
+
+	if pr.Title != title {
+		pr.Title = title
+		pr.UpdatedAt = timeNow()
+		if err := s.prRepo.Update(ctx, pr); err != nil {
+			return nil, err
 		}
+	}
 
-		reviewers, err := s.selectReviewers(author, team)
+	mapped := mapPullRequestToDTO(pr)
+	err = recordAudit(ctx, s.auditRepo, auditRecord{
+		operation:  "pr.update",
+		entityType: model.AuditEntityPullRequest,
+		entityID:   mapped.PullRequestID,
+		before:     &before,
+		after:      &mapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mapped, nil
+}
+
+func (s *pullRequestService) changeStatus(ctx context.Context, pullRequestID string, to model.PrStatus) (*dto.PullRequest, error) {
+	prID, err := parsePRID(pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dto.PullRequest
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		pr, err := s.getForUpdate(ctx, prID)
 		if err != nil {
 			return err
 		}
+		if err := authorizePRChange(ctx, pr); err != nil {
+			return err
+		}
 
-		reviewerIDs := make([]string, 0, len(reviewers))
-		for _, reviewer := range reviewers {
-			if err := s.prRepo.AddReviewer(ctx, pr.ID, reviewer.ID); err != nil {
-				return err
-			}
-			reviewerIDs = append(reviewerIDs, fmt.Sprintf("u%d", reviewer.ID))
+		result, err = s.transition(ctx, pr, to, false)
+		return err
+	})
+
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return result, nil
+}
+
+// statusOperations - имя операции в журнале для перехода в статус.
+var statusOperations = map[model.PrStatus]string{
+	model.PrStatusMerged: "pr.merge",
+	model.PrStatusClosed: "pr.close",
+	model.PrStatusOpen:   "pr.reopen",
+}
+
+// transition переводит PR, заблокированный getForUpdate, в статус to внутри
+// uow.Do. Слияние OPEN PR требует одобрений из настроек команды. force
+// пропускает эту проверку: PR уже слит во внешней системе, и сервис лишь
+// повторяет её состояние.
+func (s *pullRequestService) transition(ctx context.Context, pr *model.PullRequest, to model.PrStatus, force bool) (*dto.PullRequest, error) {
+	before := mapPullRequestToDTO(pr)
+
+	if to == model.PrStatusMerged && pr.Status == model.PrStatusOpen && !force {
+		if err := s.checkApprovals(ctx, pr); err != nil {
+			return nil, err
 		}
+	}
 
-		now := time.Now()
-		result = &dto.PullRequest{
-			PullRequestID:     req.PullRequestID,
-			PullRequestName:   req.PullRequestName,
-			AuthorID:          req.AuthorID,
-			Status:            dto.PullRequestStatusOpen,
-			AssignedReviewers: reviewerIDs,
-			CreatedAt:         &now,
+	changed, err := transitionPR(pr, to, timeNow())
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := s.prRepo.Update(ctx, pr); err != nil {
+			return nil, err
 		}
+	}
 
-		return nil
+	mapped := mapPullRequestToDTO(pr)
+
+	if changed && to == model.PrStatusMerged {
+		if err := s.outboxRepo.Enqueue(ctx, events.New(events.TypePRMerged, mapped)); err != nil {
+			return nil, err
+		}
+	}
+
+	err = recordAudit(ctx, s.auditRepo, auditRecord{
+		operation:  statusOperations[to],
+		entityType: model.AuditEntityPullRequest,
+		entityID:   mapped.PullRequestID,
+		before:     &before,
+		after:      &mapped,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &mapped, nil
+}
 
+// getForUpdate блокирует PR до конца транзакции и загружает его со связями.
+func (s *pullRequestService) getForUpdate(ctx context.Context, prID uint) (*model.PullRequest, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "PR not found",
+			}
+		}
 		return nil, err
 	}
+	return pr, nil
+}
 
-	return result, nil
+// authorizePRChange пропускает admin, автора PR и назначенных на него ревьюверов.
+// pr должен быть загружен со списком ревью.
+func authorizePRChange(ctx context.Context, pr *model.PullRequest) error {
+	actor := auth.ActorFromContext(ctx)
+	if actor.IsAdmin() || actor.ID == fmt.Sprintf("u%d", pr.AuthorID) {
+		return nil
+	}
+	for _, review := range pr.Reviews {
+		if actor.ID == fmt.Sprintf("u%d", review.ReviewerID) {
+			return nil
+		}
+	}
+	return &ServiceError{
+		Code:    dto.ErrorCodeForbidden,
+		Message: "only the PR author, its reviewers or admin can change the PR",
+	}
 }
 
-func (s *pullRequestService) MergePR(ctx context.Context, req dto.MergePRRequest) (*dto.PullRequest, error) {
+// SubmitReview сохраняет решение ревьювера. Решение за другого пользователя
+// может отправить только admin.
+func (s *pullRequestService) SubmitReview(ctx context.Context, req dto.SubmitReviewRequest) (*dto.PullRequest, error) {
 	prID, err := parsePRID(req.PullRequestID)
 	if err != nil {
 		return nil, err
 	}
 
+	reviewerID, err := parseUserID(req.ReviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor := auth.ActorFromContext(ctx); !actor.IsAdmin() && actor.ID != fmt.Sprintf("u%d", reviewerID) {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeForbidden,
+			Message: "cannot submit a review for another user",
+		}
+	}
+
 	var result *dto.PullRequest
 
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		pr, err := s.prRepo.GetByIDWithRelations(ctx, prID)
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return &ServiceError{
@@ -154,31 +429,59 @@ func (s *pullRequestService) MergePR(ctx context.Context, req dto.MergePRRequest
 			return err
 		}
 
-		pr.Status = model.PrStatusMerged
-		if err := s.prRepo.Update(ctx, pr); err != nil {
+		if pr.Status == model.PrStatusMerged {
+			return &ServiceError{
+				Code:    dto.ErrorCodePRMerged,
+				Message: "cannot review merged PR",
+			}
+		}
+		if pr.Status == model.PrStatusClosed {
+			return &ServiceError{
+				Code:    dto.ErrorCodePRClosed,
+				Message: "cannot review closed PR",
+			}
+		}
+
+		isAssigned, err := s.prRepo.IsReviewerAssigned(ctx, prID, reviewerID)
+		if err != nil {
 			return err
 		}
+		if !isAssigned {
+			return &ServiceError{
+				Code:    dto.ErrorCodeNotAssigned,
+				Message: "reviewer is not assigned to this PR",
+			}
+		}
 
-		reviewerIDs := make([]string, len(pr.Reviewers))
-		for i, reviewer := range pr.Reviewers {
-			reviewerIDs[i] = fmt.Sprintf("u%d", reviewer.ID)
+		before := mapPullRequestToDTO(pr)
+
+		now := timeNow()
+		if err := s.prRepo.SetReviewState(ctx, prID, reviewerID, model.ReviewState(req.State), now); err != nil {
+			return err
 		}
 
-		now := time.Now()
-		result = &dto.PullRequest{
-			PullRequestID:     req.PullRequestID,
-			PullRequestName:   pr.Title,
-			AuthorID:          fmt.Sprintf("u%d", pr.AuthorID),
-			Status:            dto.PullRequestStatusMerged,
-			AssignedReviewers: reviewerIDs,
-			MergedAt:          &now,
+		for i := range pr.Reviews {
+			if pr.Reviews[i].ReviewerID == reviewerID {
+				pr.Reviews[i].State = model.ReviewState(req.State)
+				pr.Reviews[i].ReviewedAt = &now
+			}
 		}
 
-		return nil
+		mapped := mapPullRequestToDTO(pr)
+		result = &mapped
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "pr.submit_review",
+			entityType: model.AuditEntityPullRequest,
+			entityID:   mapped.PullRequestID,
+			targetIDs:  []string{fmt.Sprintf("u%d", reviewerID)},
+			before:     &before,
+			after:      &mapped,
+		})
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, conflictError(err)
 	}
 
 	return result, nil
@@ -197,8 +500,10 @@ func (s *pullRequestService) ReassignReviewer(ctx context.Context, req dto.Reass
 
 	var result *dto.ReassignPRResponse
 
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		pr, err := s.prRepo.GetByIDWithRelations(ctx, prID)
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return &ServiceError{
@@ -209,12 +514,22 @@ func (s *pullRequestService) ReassignReviewer(ctx context.Context, req dto.Reass
 			return err
 		}
 
+		if err := authorizePRChange(ctx, pr); err != nil {
+			return err
+		}
+
 		if pr.Status == model.PrStatusMerged {
 			return &ServiceError{
 				Code:    dto.ErrorCodePRMerged,
 				Message: "cannot reassign on merged PR",
 			}
 		}
+		if pr.Status == model.PrStatusClosed {
+			return &ServiceError{
+				Code:    dto.ErrorCodePRClosed,
+				Message: "cannot reassign on closed PR",
+			}
+		}
 
 		isAssigned, err := s.prRepo.IsReviewerAssigned(ctx, prID, oldUserID)
 		if err != nil {
@@ -227,115 +542,278 @@ func (s *pullRequestService) ReassignReviewer(ctx context.Context, req dto.Reass
 			}
 		}
 
+		before := mapPullRequestToDTO(pr)
+
 		oldReviewer, err := s.userRepo.GetByIDWithTeams(ctx, oldUserID)
 		if err != nil {
 			return err
 		}
 
-		if len(oldReviewer.Teams) == 0 {
-			return &ServiceError{
-				Code:    dto.ErrorCodeNoCandidate,
-				Message: "old reviewer has no team",
-			}
-		}
-
-		team, err := s.teamRepo.GetByNameWithMembers(ctx, oldReviewer.Teams[0].Name)
+		newReviewer, err := s.assigner.replaceReviewer(ctx, pr, oldReviewer)
 		if err != nil {
 			return err
 		}
 
-		newReviewer, err := s.findReplacementReviewer(pr, oldReviewer, team)
+		pr, err = s.prRepo.GetByIDWithRelations(ctx, prID)
 		if err != nil {
 			return err
 		}
 
-		if err := s.prRepo.RemoveReviewer(ctx, prID, oldUserID); err != nil {
-			return err
+		result = &dto.ReassignPRResponse{
+			PR:         mapPullRequestToDTO(pr),
+			ReplacedBy: fmt.Sprintf("u%d", newReviewer.ID),
 		}
 
-		if err := s.prRepo.AddReviewer(ctx, prID, newReviewer.ID); err != nil {
+		err = s.outboxRepo.Enqueue(ctx, events.New(events.TypeReviewerReassigned, events.ReviewerReassigned{
+			PullRequestID: result.PR.PullRequestID,
+			OldReviewerID: fmt.Sprintf("u%d", oldUserID),
+			NewReviewerID: result.ReplacedBy,
+		}))
+		if err != nil {
 			return err
 		}
 
-		pr, err = s.prRepo.GetByIDWithRelations(ctx, prID)
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "pr.reassign",
+			entityType: model.AuditEntityPullRequest,
+			entityID:   result.PR.PullRequestID,
+			targetIDs:  []string{fmt.Sprintf("u%d", oldUserID), result.ReplacedBy},
+			before:     &before,
+			after:      &result.PR,
+		})
+	})
+
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return result, nil
+}
+
+const (
+	defaultListLimit = 20
+)
+
+type prListCursor struct {
+	SortBy    repository.PullRequestSortField `json:"s"`
+	Desc      bool                            `json:"d"`
+	ID        uint                            `json:"id"`
+	CreatedAt time.Time                       `json:"c"`
+}
+
+func (s *pullRequestService) ListPRs(ctx context.Context, req dto.ListPRsRequest) (*dto.ListPRsResponse, error) {
+	q := repository.PullRequestQuery{
+		Filter: repository.PullRequestFilter{
+			TeamName:    req.TeamName,
+			CreatedFrom: req.CreatedFrom,
+			CreatedTo:   req.CreatedTo,
+		},
+		SortBy: repository.PullRequestSortByID,
+		Desc:   req.Order == "desc",
+		Limit:  req.Limit,
+	}
+	if req.SortBy != "" {
+		q.SortBy = repository.PullRequestSortField(req.SortBy)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultListLimit
+	}
+
+	if req.Status != "" {
+		status := model.PrStatus(req.Status)
+		q.Filter.Status = &status
+	}
+	if req.AuthorID != "" {
+		authorID, err := parseUserID(req.AuthorID)
 		if err != nil {
-			return err
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeInvalidFilter,
+				Message: "invalid author_id: " + req.AuthorID,
+			}
 		}
-
-		reviewerIDs := make([]string, len(pr.Reviewers))
-		for i, reviewer := range pr.Reviewers {
-			reviewerIDs[i] = fmt.Sprintf("u%d", reviewer.ID)
+		q.Filter.AuthorID = &authorID
+	}
+	if req.ReviewerID != "" {
+		reviewerID, err := parseUserID(req.ReviewerID)
+		if err != nil {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeInvalidFilter,
+				Message: "invalid reviewer_id: " + req.ReviewerID,
+			}
 		}
+		q.Filter.ReviewerID = &reviewerID
+	}
 
-		result = &dto.ReassignPRResponse{
-			PR: dto.PullRequest{
-				PullRequestID:     req.PullRequestID,
-				PullRequestName:   pr.Title,
-				AuthorID:          fmt.Sprintf("u%d", pr.AuthorID),
-				Status:            dto.PullRequestStatusOpen,
-				AssignedReviewers: reviewerIDs,
-			},
-			ReplacedBy: fmt.Sprintf("u%d", newReviewer.ID),
+	if req.Cursor != "" {
+		var cursor prListCursor
+		if err := decodeCursor(req.Cursor, &cursor); err != nil {
+			return nil, err
 		}
+		if cursor.SortBy != q.SortBy || cursor.Desc != q.Desc {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeInvalidCursor,
+				Message: "cursor does not match sort_by/order",
+			}
+		}
+		q.After = &repository.PullRequestCursor{
+			ID:        cursor.ID,
+			CreatedAt: cursor.CreatedAt,
+		}
+	}
 
-		return nil
-	})
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	limit := q.Limit
+	q.Limit++
 
+	prs, err := s.prRepo.List(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &dto.ListPRsResponse{
+		PullRequests: make([]dto.PullRequest, 0, min(len(prs), limit)),
+	}
+
+	if len(prs) > limit {
+		prs = prs[:limit]
+		last := prs[len(prs)-1]
+		result.NextCursor, err = encodeCursor(prListCursor{
+			SortBy:    q.SortBy,
+			Desc:      q.Desc,
+			ID:        last.ID,
+			CreatedAt: last.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range prs {
+		result.PullRequests = append(result.PullRequests, mapPullRequestToDTO(&prs[i]))
+	}
+
 	return result, nil
 }
 
-func (s *pullRequestService) selectReviewers(author *model.User, team *model.Team) ([]model.User, error) {
-	candidates := make([]model.User, 0)
-	for _, member := range team.Members {
-		if member.ID != author.ID && member.IsActive {
-			candidates = append(candidates, member)
+// checkApprovals не даёт слить PR, пока не набрано required_approvals команды PR.
+func (s *pullRequestService) checkApprovals(ctx context.Context, pr *model.PullRequest) error {
+	var teamID uint
+	if pr.TeamID != nil {
+		teamID = *pr.TeamID
+	} else {
+		author, err := s.userRepo.GetByIDWithTeams(ctx, pr.AuthorID)
+		if err != nil {
+			return err
 		}
+		primary := author.PrimaryTeam()
+		if primary == nil {
+			return nil
+		}
+		teamID = primary.ID
 	}
 
-	if len(candidates) == 0 {
-		return []model.User{}, nil
+	team, err := s.teamRepo.GetByIDWithMembers(ctx, teamID)
+	if err != nil {
+		return err
 	}
 
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
+	settings := teamSettingsOrDefault(team)
+	if settings.RequiredApprovals == 0 {
+		return nil
+	}
 
-	maxReviewers := 2
-	if len(candidates) < maxReviewers {
-		maxReviewers = len(candidates)
+	approvals := 0
+	for _, review := range pr.Reviews {
+		if review.State == model.ReviewStateApproved {
+			approvals++
+		}
 	}
 
-	return candidates[:maxReviewers], nil
+	if approvals < settings.RequiredApprovals {
+		return &ServiceError{
+			Code:    dto.ErrorCodeNotEnoughApprovals,
+			Message: fmt.Sprintf("PR has %d of %d required approvals", approvals, settings.RequiredApprovals),
+		}
+	}
+	return nil
 }
 
-func (s *pullRequestService) findReplacementReviewer(pr *model.PullRequest, oldReviewer *model.User, team *model.Team) (*model.User, error) {
-	assignedIDs := make(map[uint]bool)
-	for _, reviewer := range pr.Reviewers {
-		assignedIDs[reviewer.ID] = true
+// resolveReviewTeam выбирает команду ревьюверов: явно указанную, если автор в
+// ней состоит, иначе основную команду автора.
+func resolveReviewTeam(author *model.User, teamName string) (uint, error) {
+	if teamName == "" {
+		primary := author.PrimaryTeam()
+		if primary == nil {
+			return 0, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "author has no team",
+			}
+		}
+		return primary.ID, nil
 	}
 
-	candidates := make([]model.User, 0)
-	for _, member := range team.Members {
-		if member.ID != pr.AuthorID && // не автор
-			member.IsActive && // активен
-			member.ID != oldReviewer.ID && // не старый ревьювер
-			!assignedIDs[member.ID] { // еще не назначен
-			candidates = append(candidates, member)
+	for _, membership := range author.Memberships {
+		if membership.Team.Name == teamName {
+			return membership.TeamID, nil
 		}
 	}
+	return 0, &ServiceError{
+		Code:    dto.ErrorCodeNotMember,
+		Message: fmt.Sprintf("author is not a member of team %s", teamName),
+	}
+}
+
+func mapPullRequestToDTO(pr *model.PullRequest) dto.PullRequest {
+	reviewerIDs := make([]string, len(pr.Reviewers))
+	for i, reviewer := range pr.Reviewers {
+		reviewerIDs[i] = fmt.Sprintf("u%d", reviewer.ID)
+	}
 
-	if len(candidates) == 0 {
-		return nil, &ServiceError{
-			Code:    dto.ErrorCodeNoCandidate,
-			Message: "no active replacement candidate in team",
-		}
+	var reviews []dto.Review
+	for _, review := range pr.Reviews {
+		reviews = append(reviews, dto.Review{
+			UserID:     fmt.Sprintf("u%d", review.ReviewerID),
+			State:      dto.ReviewState(review.State),
+			ReviewedAt: review.ReviewedAt,
+		})
 	}
 
-	return &candidates[rand.Intn(len(candidates))], nil
+	teamName := ""
+	if pr.Team != nil {
+		teamName = pr.Team.Name
+	}
+
+	createdAt, updatedAt := pr.CreatedAt, pr.UpdatedAt
+	return dto.PullRequest{
+		PullRequestID:     fmt.Sprintf("pr-%d", pr.ID),
+		PullRequestName:   pr.Title,
+		AuthorID:          fmt.Sprintf("u%d", pr.AuthorID),
+		TeamName:          teamName,
+		Status:            dto.PullRequestStatus(pr.Status),
+		AssignedReviewers: reviewerIDs,
+		Reviews:           reviews,
+		CreatedAt:         &createdAt,
+		UpdatedAt:         &updatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+func mapPullRequestToShortDTO(pr *model.PullRequest) dto.PullRequestShort {
+	createdAt, updatedAt := pr.CreatedAt, pr.UpdatedAt
+	return dto.PullRequestShort{
+		PullRequestID:   fmt.Sprintf("pr-%d", pr.ID),
+		PullRequestName: pr.Title,
+		AuthorID:        fmt.Sprintf("u%d", pr.AuthorID),
+		Status:          dto.PullRequestStatus(pr.Status),
+		CreatedAt:       &createdAt,
+		UpdatedAt:       &updatedAt,
+		MergedAt:        pr.MergedAt,
+	}
+}
+
+// timeNow - текущее время с точностью, которую хранит БД.
+func timeNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
 }
 
 func parsePRID(prID string) (uint, error) {
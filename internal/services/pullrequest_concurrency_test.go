@@ -0,0 +1,200 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/migrate"
+	"go-rest-api/internal/services"
+)
+
+// openTestDB подключается к базе с применёнными миграциями из TEST_DATABASE_DSN.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	return conn
+}
+
+// openSQLiteDB создаёт базу SQLite во временном каталоге с теми же настройками,
+// что и сервис, и применяет к ней миграции. Так тесты параллельных операций
+// проходят через настоящие репозитории и транзакции без внешней базы.
+func openSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") +
+		"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+	conn, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := conn.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	provider, err := migrate.NewProvider(sqlDB, config.DriverSQLite)
+	if err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return conn
+}
+
+func TestCreatePRConcurrentSameID(t *testing.T) {
+	testCreatePRConcurrentSameID(t, openTestDB(t))
+}
+
+func TestCreatePRConcurrentSameIDSQLite(t *testing.T) {
+	testCreatePRConcurrentSameID(t, openSQLiteDB(t))
+}
+
+// concurrencySetup создаёт сервисы поверх conn и команду из size активных
+// участников с уникальными id, чтобы тесты не мешали друг другу в общей базе.
+func concurrencySetup(t *testing.T, conn *gorm.DB, size int) (services.PullRequestService, []dto.TeamMember, int) {
+	t.Helper()
+
+	uow := db.NewTxManager(conn, config.TxRetry{
+		MaxAttempts: 3,
+		BaseBackoff: 10 * time.Millisecond,
+		MaxBackoff:  100 * time.Millisecond,
+	})
+	repos := db.NewRepositories(conn)
+	selectors := services.NewReviewerSelectors(repos.PullRequests)
+	teamService := services.NewTeamService(uow, repos, selectors)
+	prService := services.NewPullRequestService(uow, repos, selectors)
+
+	base := rand.Intn(1_000_000_000) + 1_000_000
+	members := make([]dto.TeamMember, size)
+	for i := range members {
+		members[i] = dto.TeamMember{
+			UserID:   fmt.Sprintf("u%d", base+i),
+			Username: fmt.Sprintf("user%d", i),
+			IsActive: true,
+		}
+	}
+	if _, err := teamService.CreateTeam(adminContext(), dto.CreateTeamRequest{
+		TeamName: fmt.Sprintf("concurrency-%d", base),
+		Members:  members,
+	}); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	return prService, members, base
+}
+
+// runConcurrently запускает call в workers горутинах одновременно и
+// возвращает их ошибки.
+func runConcurrently(workers int, call func() error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, workers)
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = call()
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+// assertOneSucceeded проверяет, что успешен ровно один вызов, а остальные
+// получили ошибку с кодом code.
+func assertOneSucceeded(t *testing.T, errs []error, code dto.ErrorCode) {
+	t.Helper()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var serviceErr *services.ServiceError
+		if !errors.As(err, &serviceErr) || serviceErr.Code != code {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("succeeded = %d, want 1", succeeded)
+	}
+}
+
+func testCreatePRConcurrentSameID(t *testing.T, conn *gorm.DB) {
+	prService, members, base := concurrencySetup(t, conn, 3)
+
+	req := dto.CreatePRRequest{
+		PullRequestID:   fmt.Sprintf("pr-%d", base),
+		PullRequestName: "concurrent",
+		AuthorID:        members[0].UserID,
+	}
+	errs := runConcurrently(8, func() error {
+		_, err := prService.CreatePR(adminContext(), req)
+		return err
+	})
+	assertOneSucceeded(t, errs, dto.ErrorCodePRExists)
+}
+
+func TestReassignReviewerConcurrentSameReviewer(t *testing.T) {
+	testReassignReviewerConcurrentSameReviewer(t, openTestDB(t))
+}
+
+func TestReassignReviewerConcurrentSameReviewerSQLite(t *testing.T) {
+	testReassignReviewerConcurrentSameReviewer(t, openSQLiteDB(t))
+}
+
+func testReassignReviewerConcurrentSameReviewer(t *testing.T, conn *gorm.DB) {
+	// автор, два ревьювера и свободные кандидаты на замену
+	prService, members, base := concurrencySetup(t, conn, 6)
+
+	pr, err := prService.CreatePR(adminContext(), dto.CreatePRRequest{
+		PullRequestID:   fmt.Sprintf("pr-%d", base),
+		PullRequestName: "concurrent",
+		AuthorID:        members[0].UserID,
+	})
+	if err != nil {
+		t.Fatalf("create PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) == 0 {
+		t.Fatal("no reviewers assigned")
+	}
+
+	req := dto.ReassignPRRequest{
+		PullRequestID: pr.PullRequestID,
+		OldUserID:     pr.AssignedReviewers[0],
+	}
+	errs := runConcurrently(8, func() error {
+		_, err := prService.ReassignReviewer(adminContext(), req)
+		return err
+	})
+	assertOneSucceeded(t, errs, dto.ErrorCodeNotAssigned)
+}
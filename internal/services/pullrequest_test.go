@@ -0,0 +1,564 @@
+package services_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/events"
+)
+
+// openPR создаёт команду backend (u1, u2, u3) и PR pr-1 автора u1 с ревьюверами u2 и u3.
+func openPR(t *testing.T, f *fixture) {
+	f.createTeam(t, "backend", "u1", "u2", "u3")
+	f.createPR(t, "pr-1", "u1")
+}
+
+func mergedPR(t *testing.T, f *fixture) {
+	openPR(t, f)
+	f.mergePR(t, "pr-1")
+}
+
+func closedPR(t *testing.T, f *fixture) {
+	openPR(t, f)
+	f.closePR(t, "pr-1")
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestPullRequestServiceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, f *fixture)
+		// conflicting - транзакции сервисов после setup не проходят из-за конфликтов
+		conflicting bool
+		call        func(ctx context.Context, f *fixture) error
+		want        dto.ErrorCode
+	}{
+		{
+			name: "create",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2", "u3")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1"})
+				return err
+			},
+		},
+		{
+			name:  "create existing id",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodePRExists,
+		},
+		{
+			name: "create with unknown author",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u9"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "create by author without team",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2", "u3")
+				_, err := f.teams.RemoveMembers(adminContext(), dto.RemoveTeamMembersRequest{TeamName: "backend", UserIDs: []string{"u1"}})
+				mustSucceed(t, err)
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "create for team of another author",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2")
+				f.createTeam(t, "frontend", "u3", "u4")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1", TeamName: "frontend"})
+				return err
+			},
+			want: dto.ErrorCodeNotMember,
+		},
+		{
+			name: "create below min_reviewers",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2")
+				f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", MinReviewers: intPtr(2), MaxReviewers: intPtr(2)})
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1"})
+				return err
+			},
+			want: dto.ErrorCodeNoCandidate,
+		},
+		{
+			name: "create while reviewers are unavailable",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2")
+				f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", MinReviewers: intPtr(1)})
+				now := time.Now()
+				_, err := f.users.AddUnavailability(adminContext(), dto.AddUnavailabilityRequest{
+					UserID:   "u2",
+					StartsAt: now.Add(-time.Hour),
+					EndsAt:   now.Add(time.Hour),
+				})
+				mustSucceed(t, err)
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1"})
+				return err
+			},
+			want: dto.ErrorCodeNoCandidate,
+		},
+		{
+			name: "merge unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.MergePR(ctx, dto.MergePRRequest{PullRequestID: "pr-404"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "merge closed PR",
+			setup: closedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.MergePR(ctx, dto.MergePRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidTransition,
+		},
+		{
+			name: "merge without required approvals",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2", "u3")
+				f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(1)})
+				f.createPR(t, "pr-1", "u1")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.MergePR(ctx, dto.MergePRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+			want: dto.ErrorCodeNotEnoughApprovals,
+		},
+		{
+			name: "merge with required approvals",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2", "u3")
+				f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(1)})
+				f.createPR(t, "pr-1", "u1")
+				_, err := f.prs.SubmitReview(adminContext(), dto.SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u2", State: dto.ReviewStateApproved})
+				mustSucceed(t, err)
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.MergePR(ctx, dto.MergePRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+		},
+		{
+			name:        "merge with exhausted retries",
+			setup:       openPR,
+			conflicting: true,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.MergePR(ctx, dto.MergePRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+			want: dto.ErrorCodeConcurrentUpdate,
+		},
+		{
+			name: "close unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ClosePR(ctx, dto.ClosePRRequest{PullRequestID: "pr-404"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "close merged PR",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ClosePR(ctx, dto.ClosePRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidTransition,
+		},
+		{
+			name: "reopen unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReopenPR(ctx, dto.ReopenPRRequest{PullRequestID: "pr-404"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "reopen merged PR",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReopenPR(ctx, dto.ReopenPRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidTransition,
+		},
+		{
+			name:  "reopen closed PR",
+			setup: closedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReopenPR(ctx, dto.ReopenPRRequest{PullRequestID: "pr-1"})
+				return err
+			},
+		},
+		{
+			name: "update unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.UpdatePR(ctx, dto.UpdatePRRequest{PullRequestID: "pr-404", PullRequestName: "new"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "update merged PR",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.UpdatePR(ctx, dto.UpdatePRRequest{PullRequestID: "pr-1", PullRequestName: "new"})
+				return err
+			},
+			want: dto.ErrorCodePRMerged,
+		},
+		{
+			name: "review unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-404", ReviewerID: "u2", State: dto.ReviewStateApproved})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "review merged PR",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u2", State: dto.ReviewStateApproved})
+				return err
+			},
+			want: dto.ErrorCodePRMerged,
+		},
+		{
+			name:  "review closed PR",
+			setup: closedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u2", State: dto.ReviewStateApproved})
+				return err
+			},
+			want: dto.ErrorCodePRClosed,
+		},
+		{
+			name:  "review by user who is not a reviewer",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: dto.ReviewStateApproved})
+				return err
+			},
+			want: dto.ErrorCodeNotAssigned,
+		},
+		{
+			name: "reassign unknown PR",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-404", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "reassign on merged PR",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodePRMerged,
+		},
+		{
+			name:  "reassign on closed PR",
+			setup: closedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodePRClosed,
+		},
+		{
+			name:  "reassign user who is not a reviewer",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u1"})
+				return err
+			},
+			want: dto.ErrorCodeNotAssigned,
+		},
+		{
+			name:  "reassign without free team members",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodeNoCandidate,
+		},
+		{
+			name: "reassign when PR and old reviewer have no team",
+			setup: func(t *testing.T, f *fixture) {
+				openPR(t, f)
+				ctx := adminContext()
+				pr, err := f.repos.PullRequests.GetByID(ctx, 1)
+				mustSucceed(t, err)
+				pr.TeamID = nil
+				mustSucceed(t, f.repos.PullRequests.Update(ctx, pr))
+				_, err = f.teams.RemoveMembers(ctx, dto.RemoveTeamMembersRequest{TeamName: "backend", UserIDs: []string{"u2"}})
+				mustSucceed(t, err)
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodeNoCandidate,
+		},
+		{
+			name: "create with exhausted retries",
+			setup: func(t *testing.T, f *fixture) {
+				f.createTeam(t, "backend", "u1", "u2", "u3")
+			},
+			conflicting: true,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "p", AuthorID: "u1"})
+				return err
+			},
+			want: dto.ErrorCodeConcurrentUpdate,
+		},
+		{
+			name:        "reassign with exhausted retries",
+			setup:       openPR,
+			conflicting: true,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+				return err
+			},
+			want: dto.ErrorCodeConcurrentUpdate,
+		},
+		{
+			name: "list with malformed cursor",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ListPRs(ctx, dto.ListPRsRequest{Cursor: "garbage"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidCursor,
+		},
+		{
+			name: "list with malformed author filter",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ListPRs(ctx, dto.ListPRsRequest{AuthorID: "alice"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidFilter,
+		},
+		{
+			name: "list with malformed reviewer filter",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.prs.ListPRs(ctx, dto.ListPRsRequest{ReviewerID: "u-2"})
+				return err
+			},
+			want: dto.ErrorCodeInvalidFilter,
+		},
+		{
+			name: "list with cursor of another sort order",
+			setup: func(t *testing.T, f *fixture) {
+				openPR(t, f)
+				f.createPR(t, "pr-2", "u1")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				page, err := f.prs.ListPRs(ctx, dto.ListPRsRequest{Limit: 1})
+				if err != nil {
+					return err
+				}
+				_, err = f.prs.ListPRs(ctx, dto.ListPRsRequest{Limit: 1, SortBy: "created_at", Cursor: page.NextCursor})
+				return err
+			},
+			want: dto.ErrorCodeInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+			if tt.conflicting {
+				f = newFixtureWithUoW(f.store, conflictingUoW{})
+			}
+
+			assertCode(t, tt.call(adminContext(), f), tt.want)
+		})
+	}
+}
+
+func TestCreatePRAssignsReviewersAndEnqueuesEvents(t *testing.T) {
+	f := newFixture()
+	f.createTeam(t, "backend", "u1", "u2", "u3")
+
+	pr := f.createPR(t, "pr-1", "u1")
+	slices.Sort(pr.AssignedReviewers)
+	if !slices.Equal(pr.AssignedReviewers, []string{"u2", "u3"}) {
+		t.Fatalf("reviewers = %v, want [u2 u3]", pr.AssignedReviewers)
+	}
+
+	var types []string
+	for _, msg := range f.store.OutboxMessages() {
+		types = append(types, msg.EventType)
+	}
+	want := []string{
+		string(events.TypeTeamCreated),
+		string(events.TypePRCreated),
+		string(events.TypeReviewerAssigned),
+		string(events.TypeReviewerAssigned),
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("outbox = %v, want %v", types, want)
+	}
+}
+
+func TestFailedCreatePRLeavesNoTrace(t *testing.T) {
+	f := newFixture()
+	f.createTeam(t, "backend", "u1", "u2")
+	f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", MinReviewers: intPtr(2), MaxReviewers: intPtr(2)})
+
+	_, err := f.prs.CreatePR(adminContext(), dto.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "PR", AuthorID: "u1"})
+	assertCode(t, err, dto.ErrorCodeNoCandidate)
+
+	exists, err := f.repos.PullRequests.ExistsByID(adminContext(), 1)
+	mustSucceed(t, err)
+	if exists {
+		t.Fatal("PR is stored after failed CreatePR")
+	}
+	if n := len(f.store.OutboxMessages()); n != 1 {
+		t.Fatalf("outbox has %d messages, want only team.created", n)
+	}
+}
+
+func TestReassignReviewerPicksFreeMember(t *testing.T) {
+	f := newFixture()
+	openPR(t, f)
+	_, err := f.teams.AddMembers(adminContext(), dto.AddTeamMembersRequest{
+		TeamName: "backend",
+		Members:  []dto.TeamMember{{UserID: "u4", Username: "user-u4", IsActive: true}},
+	})
+	mustSucceed(t, err)
+
+	resp, err := f.prs.ReassignReviewer(adminContext(), dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	mustSucceed(t, err)
+	if resp.ReplacedBy != "u4" {
+		t.Fatalf("replaced_by = %s, want u4", resp.ReplacedBy)
+	}
+	if !slices.Equal(resp.PR.AssignedReviewers, []string{"u3", "u4"}) {
+		t.Fatalf("reviewers = %v, want [u3 u4]", resp.PR.AssignedReviewers)
+	}
+}
+
+func TestRepeatedMergeIsAuditedOnce(t *testing.T) {
+	f := newFixture()
+	mergedPR(t, f)
+	f.mergePR(t, "pr-1")
+
+	var operations []string
+	for _, entry := range f.store.AuditEntries() {
+		if entry.EntityID == "pr-1" {
+			operations = append(operations, entry.Operation)
+		}
+	}
+	if want := []string{"pr.create", "pr.merge"}; !slices.Equal(operations, want) {
+		t.Fatalf("audit = %v, want %v", operations, want)
+	}
+}
+
+func TestFailedMergeIsNotAudited(t *testing.T) {
+	f := newFixture()
+	openPR(t, f)
+	f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(1)})
+
+	_, err := f.prs.MergePR(adminContext(), dto.MergePRRequest{PullRequestID: "pr-1"})
+	assertCode(t, err, dto.ErrorCodeNotEnoughApprovals)
+
+	for _, entry := range f.store.AuditEntries() {
+		if entry.Operation == "pr.merge" {
+			t.Fatalf("failed merge is audited: %+v", entry)
+		}
+	}
+}
+
+func TestPullRequestChangesRequireParticipant(t *testing.T) {
+	closePR := func(ctx context.Context, f *fixture) error {
+		_, err := f.prs.ClosePR(ctx, dto.ClosePRRequest{PullRequestID: "pr-1"})
+		return err
+	}
+	reassign := func(ctx context.Context, f *fixture) error {
+		_, err := f.prs.ReassignReviewer(ctx, dto.ReassignPRRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+		return err
+	}
+	create := func(authorID string) func(ctx context.Context, f *fixture) error {
+		return func(ctx context.Context, f *fixture) error {
+			_, err := f.prs.CreatePR(ctx, dto.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "PR pr-2", AuthorID: authorID})
+			return err
+		}
+	}
+	review := func(reviewerID string) func(ctx context.Context, f *fixture) error {
+		return func(ctx context.Context, f *fixture) error {
+			_, err := f.prs.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: reviewerID, State: dto.ReviewStateApproved})
+			return err
+		}
+	}
+
+	tests := []struct {
+		name  string
+		actor string
+		call  func(ctx context.Context, f *fixture) error
+		want  dto.ErrorCode
+	}{
+		{name: "author closes", actor: "u1", call: closePR},
+		{name: "reviewer closes", actor: "u2", call: closePR},
+		{name: "outsider closes", actor: "u4", call: closePR, want: dto.ErrorCodeForbidden},
+		{name: "reviewer reassigns", actor: "u3", call: reassign},
+		{name: "outsider reassigns", actor: "u4", call: reassign, want: dto.ErrorCodeForbidden},
+		{name: "reviewer submits own review", actor: "u2", call: review("u2")},
+		{name: "reviewer submits review of another", actor: "u3", call: review("u2"), want: dto.ErrorCodeForbidden},
+		{name: "author submits review of reviewer", actor: "u1", call: review("u2"), want: dto.ErrorCodeForbidden},
+		{name: "user creates own PR", actor: "u4", call: create("u4")},
+		{name: "user creates PR for another author", actor: "u4", call: create("u1"), want: dto.ErrorCodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// u2 и u3 - ревьюверы pr-1, u4 вступил в команду после его создания
+			f := newFixture()
+			openPR(t, f)
+			_, err := f.teams.AddMembers(adminContext(), dto.AddTeamMembersRequest{
+				TeamName: "backend",
+				Members:  []dto.TeamMember{{UserID: "u4", Username: "user-u4", IsActive: true}},
+			})
+			mustSucceed(t, err)
+
+			assertCode(t, tt.call(userContext(tt.actor), f), tt.want)
+		})
+	}
+}
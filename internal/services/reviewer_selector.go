@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+const (
+	ReviewerStrategyLeastLoaded = "least_loaded"
+	ReviewerStrategyRandom      = "random"
+)
+
+// ReviewerSelector выбирает до n ревьюверов из кандидатов.
+type ReviewerSelector interface {
+	Select(ctx context.Context, candidates []model.User, n int) ([]model.User, error)
+}
+
+// ReviewerSelectors - стратегии выбора ревьюверов по имени из настроек команды.
+type ReviewerSelectors map[string]ReviewerSelector
+
+func NewReviewerSelectors(prRepo repository.PullRequestRepository) ReviewerSelectors {
+	return ReviewerSelectors{
+		ReviewerStrategyLeastLoaded: NewLeastLoadedSelector(prRepo),
+		ReviewerStrategyRandom:      NewRandomSelector(),
+	}
+}
+
+func (s ReviewerSelectors) Get(strategy string) ReviewerSelector {
+	if selector, ok := s[strategy]; ok {
+		return selector
+	}
+	return s[ReviewerStrategyLeastLoaded]
+}
+
+// withPullRequests возвращает стратегии, читающие нагрузку через prRepo. Внутри
+// транзакции least_loaded так учитывает назначения, сделанные в ней же.
+func (s ReviewerSelectors) withPullRequests(prRepo repository.PullRequestRepository) ReviewerSelectors {
+	bound := make(ReviewerSelectors, len(s))
+	for strategy, selector := range s {
+		if _, ok := selector.(*leastLoadedSelector); ok {
+			selector = NewLeastLoadedSelector(prRepo)
+		}
+		bound[strategy] = selector
+	}
+	return bound
+}
+
+func isKnownReviewerStrategy(strategy string) bool {
+	switch strategy {
+	case ReviewerStrategyLeastLoaded, ReviewerStrategyRandom:
+		return true
+	default:
+		return false
+	}
+}
+
+type randomSelector struct{}
+
+func NewRandomSelector() ReviewerSelector {
+	return &randomSelector{}
+}
+
+func (s *randomSelector) Select(_ context.Context, candidates []model.User, n int) ([]model.User, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return []model.User{}, nil
+	}
+
+	shuffled := shuffleUsers(candidates)
+	return shuffled[:min(n, len(shuffled))], nil
+}
+
+// leastLoadedSelector выбирает кандидатов с наименьшим числом OPEN ревью,
+// при равенстве - случайно.
+type leastLoadedSelector struct {
+	prRepo repository.PullRequestRepository
+}
+
+func NewLeastLoadedSelector(prRepo repository.PullRequestRepository) ReviewerSelector {
+	return &leastLoadedSelector{
+		prRepo: prRepo,
+	}
+}
+
+func (s *leastLoadedSelector) Select(ctx context.Context, candidates []model.User, n int) ([]model.User, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return []model.User{}, nil
+	}
+
+	ids := make([]uint, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+
+	load, err := s.prRepo.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	shuffled := shuffleUsers(candidates)
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return load[shuffled[i].ID] < load[shuffled[j].ID]
+	})
+
+	return shuffled[:min(n, len(shuffled))], nil
+}
+
+func shuffleUsers(users []model.User) []model.User {
+	shuffled := make([]model.User, len(users))
+	copy(shuffled, users)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
@@ -0,0 +1,34 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/services"
+)
+
+func TestSelectorsReturnEmptySliceWithoutCandidates(t *testing.T) {
+	// без кандидатов репозиторий не нужен ни одной стратегии
+	selectors := services.NewReviewerSelectors(nil)
+	candidates := []model.User{{ID: 1}, {ID: 2}}
+
+	for strategy, selector := range selectors {
+		for _, tt := range []struct {
+			name       string
+			candidates []model.User
+			n          int
+		}{
+			{"no candidates", nil, 2},
+			{"zero reviewers", candidates, 0},
+		} {
+			selected, err := selector.Select(context.Background(), tt.candidates, tt.n)
+			if err != nil {
+				t.Fatalf("%s, %s: err = %v", strategy, tt.name, err)
+			}
+			if selected == nil || len(selected) != 0 {
+				t.Errorf("%s, %s: selected = %#v, want empty non-nil slice", strategy, tt.name, selected)
+			}
+		}
+	}
+}
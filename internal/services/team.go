@@ -9,33 +9,69 @@ import (
 	"gorm.io/gorm"
 
 	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
 	"go-rest-api/internal/db/model"
 	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
 )
 
 type TeamService interface {
 	CreateTeam(ctx context.Context, req dto.CreateTeamRequest) (*dto.Team, error)
 	GetTeam(ctx context.Context, teamName string) (*dto.Team, error)
+	GetSettings(ctx context.Context, teamName string) (*dto.TeamSettings, error)
+	UpdateSettings(ctx context.Context, req dto.UpdateTeamSettingsRequest) (*dto.TeamSettings, error)
+	DeactivateUsers(ctx context.Context, req dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error)
+	AddMembers(ctx context.Context, req dto.AddTeamMembersRequest) (*dto.Team, error)
+	RemoveMembers(ctx context.Context, req dto.RemoveTeamMembersRequest) (*dto.RemoveTeamMembersResponse, error)
+	RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*dto.Team, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+	SyncTeam(ctx context.Context, req dto.SyncTeamRequest) (*dto.SyncTeamResponse, error)
 }
 
 type teamService struct {
-	db       *gorm.DB
-	teamRepo repository.TeamRepository
-	userRepo repository.UserRepository
+	uow        db.UnitOfWork
+	teamRepo   repository.TeamRepository
+	userRepo   repository.UserRepository
+	prRepo     repository.PullRequestRepository
+	outboxRepo repository.OutboxRepository
+	auditRepo  repository.AuditRepository
+	selectors  ReviewerSelectors
+	assigner   *reviewerAssigner
 }
 
-func NewTeamService(db *gorm.DB, teamRepo repository.TeamRepository, userRepo repository.UserRepository) TeamService {
+func NewTeamService(uow db.UnitOfWork, repos db.Repositories, selectors ReviewerSelectors) TeamService {
 	return &teamService{
-		db:       db,
-		teamRepo: teamRepo,
-		userRepo: userRepo,
+		uow:        uow,
+		teamRepo:   repos.Teams,
+		userRepo:   repos.Users,
+		prRepo:     repos.PullRequests,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  selectors,
+		assigner:   newReviewerAssigner(repos, selectors),
+	}
+}
+
+// bind возвращает копию сервиса, работающую через репозитории транзакции.
+func (s *teamService) bind(repos db.Repositories) *teamService {
+	return &teamService{
+		uow:        s.uow,
+		teamRepo:   repos.Teams,
+		userRepo:   repos.Users,
+		prRepo:     repos.PullRequests,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  s.selectors,
+		assigner:   newReviewerAssigner(repos, s.selectors),
 	}
 }
 
 func (s *teamService) CreateTeam(ctx context.Context, req dto.CreateTeamRequest) (*dto.Team, error) {
 	var result *dto.Team
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
 		exists, err := s.teamRepo.ExistsByName(ctx, req.TeamName)
 		if err != nil {
 			return err
@@ -47,38 +83,26 @@ func (s *teamService) CreateTeam(ctx context.Context, req dto.CreateTeamRequest)
 			}
 		}
 
-		userIDs := make([]uint, 0, len(req.Members))
-		for _, member := range req.Members {
-			userID, err := strconv.ParseUint(member.UserID, 10, 32)
-			if err != nil {
-				var id uint
-				_, err = fmt.Sscanf(member.UserID, "u%d", &id)
-				if err != nil {
-					return fmt.Errorf("invalid user_id format: %s", member.UserID)
-				}
-				userID = uint64(id)
-			}
-
-			user, err := s.userRepo.UpsertUser(ctx, uint(userID), member.Username, member.IsActive)
-			if err != nil {
-				return err
-			}
-			userIDs = append(userIDs, user.ID)
+		userIDs, err := s.upsertMembers(ctx, req.Members)
+		if err != nil {
+			return err
 		}
 
 		team := &model.Team{
 			Name: req.TeamName,
 		}
 		if err := s.teamRepo.Create(ctx, team); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return &ServiceError{
+					Code:    dto.ErrorCodeTeamExists,
+					Message: "team_name already exists",
+				}
+			}
 			return err
 		}
 
 		for _, userID := range userIDs {
-			userTeam := &model.UserTeam{
-				UserID: userID,
-				TeamID: team.ID,
-			}
-			if err := tx.Create(userTeam).Error; err != nil {
+			if err := s.teamRepo.AddMember(ctx, team.ID, userID); err != nil {
 				return err
 			}
 		}
@@ -87,7 +111,17 @@ func (s *teamService) CreateTeam(ctx context.Context, req dto.CreateTeamRequest)
 			TeamName: req.TeamName,
 			Members:  req.Members,
 		}
-		return nil
+		if err := s.outboxRepo.Enqueue(ctx, events.New(events.TypeTeamCreated, *result)); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.create",
+			entityType: model.AuditEntityTeam,
+			entityID:   req.TeamName,
+			targetIDs:  teamMemberIDs(result.Members),
+			after:      result,
+		})
 	})
 
 	if err != nil {
@@ -112,6 +146,221 @@ func (s *teamService) GetTeam(ctx context.Context, teamName string) (*dto.Team,
 	return mapTeamToDTO(team), nil
 }
 
+func (s *teamService) GetSettings(ctx context.Context, teamName string) (*dto.TeamSettings, error) {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "team not found",
+			}
+		}
+		return nil, err
+	}
+
+	settings := teamSettingsOrDefault(team)
+	return mapTeamSettingsToDTO(team.Name, &settings), nil
+}
+
+func (s *teamService) UpdateSettings(ctx context.Context, req dto.UpdateTeamSettingsRequest) (*dto.TeamSettings, error) {
+	var result *dto.TeamSettings
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &ServiceError{
+					Code:    dto.ErrorCodeNotFound,
+					Message: "team not found",
+				}
+			}
+			return err
+		}
+
+		settings := teamSettingsOrDefault(team)
+		before := mapTeamSettingsToDTO(team.Name, &settings)
+
+		if req.MinReviewers != nil {
+			settings.MinReviewers = *req.MinReviewers
+		}
+		if req.MaxReviewers != nil {
+			settings.MaxReviewers = *req.MaxReviewers
+		}
+		if req.ReviewerStrategy != nil {
+			settings.ReviewerStrategy = *req.ReviewerStrategy
+		}
+		if req.RequiredApprovals != nil {
+			settings.RequiredApprovals = *req.RequiredApprovals
+		}
+
+		if settings.MinReviewers > settings.MaxReviewers {
+			return &ServiceError{
+				Code:    dto.ErrorCodeInvalidSettings,
+				Message: "min_reviewers must not exceed max_reviewers",
+			}
+		}
+		if settings.RequiredApprovals > settings.MaxReviewers {
+			return &ServiceError{
+				Code:    dto.ErrorCodeInvalidSettings,
+				Message: "required_approvals must not exceed max_reviewers",
+			}
+		}
+		if !isKnownReviewerStrategy(settings.ReviewerStrategy) {
+			return &ServiceError{
+				Code:    dto.ErrorCodeInvalidSettings,
+				Message: fmt.Sprintf("unknown reviewer_strategy: %s", settings.ReviewerStrategy),
+			}
+		}
+
+		if err := s.teamRepo.SaveSettings(ctx, &settings); err != nil {
+			return err
+		}
+
+		result = mapTeamSettingsToDTO(team.Name, &settings)
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.update_settings",
+			entityType: model.AuditEntityTeam,
+			entityID:   team.Name,
+			before:     before,
+			after:      result,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeactivateUsers выключает участников команды и распределяет их OPEN ревью на PR
+// этой команды между оставшимися участниками. Ревью на PR других команд не
+// трогаются.
+func (s *teamService) DeactivateUsers(ctx context.Context, req dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error) {
+	userIDs := make([]uint, 0, len(req.UserIDs))
+	for _, rawID := range req.UserIDs {
+		userID, err := parseUserID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	var result *dto.DeactivateTeamUsersResponse
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.teamRepo.GetByNameWithMembers(ctx, req.TeamName)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &ServiceError{
+					Code:    dto.ErrorCodeNotFound,
+					Message: "team not found",
+				}
+			}
+			return err
+		}
+
+		members := make(map[uint]bool, len(team.Members))
+		for _, member := range team.Members {
+			members[member.ID] = true
+		}
+
+		leaving := make(map[uint]bool, len(userIDs))
+		for _, userID := range userIDs {
+			if !members[userID] {
+				return &ServiceError{
+					Code:    dto.ErrorCodeNotMember,
+					Message: fmt.Sprintf("user u%d is not a member of team", userID),
+				}
+			}
+			leaving[userID] = true
+		}
+
+		before := mapTeamToDTO(team)
+
+		if err := s.userRepo.SetActive(ctx, userIDs, false); err != nil {
+			return err
+		}
+
+		assignments, err := s.assigner.lockLeavingAssignments(ctx, leaving)
+		if err != nil {
+			return err
+		}
+		assignments = teamAssignments(assignments, team.ID)
+
+		plan, err := s.assigner.planBulkReassignment(ctx, team, leaving, assignments)
+		if err != nil {
+			return err
+		}
+
+		if err := s.prRepo.ReplaceReviewers(ctx, plan.moves); err != nil {
+			return err
+		}
+
+		deactivated := make([]string, 0, len(leaving))
+		for i, member := range team.Members {
+			if leaving[member.ID] {
+				team.Members[i].IsActive = false
+				deactivated = append(deactivated, fmt.Sprintf("u%d", member.ID))
+			}
+		}
+
+		result = &dto.DeactivateTeamUsersResponse{
+			TeamName:         team.Name,
+			DeactivatedUsers: deactivated,
+			PullRequests:     plan.reports,
+		}
+
+		after := mapTeamToDTO(team)
+		published := append(plan.reassignedEvents(), events.New(events.TypeTeamUpdated, *after))
+		if err := s.outboxRepo.Enqueue(ctx, published...); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.deactivate_users",
+			entityType: model.AuditEntityTeam,
+			entityID:   team.Name,
+			targetIDs:  append(deactivated, reassignmentTargets(plan.reports)...),
+			before:     before,
+			after:      after,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// upsertMembers создаёт или обновляет пользователей из запроса и возвращает их id.
+func (s *teamService) upsertMembers(ctx context.Context, members []dto.TeamMember) ([]uint, error) {
+	userIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		userID, err := strconv.ParseUint(member.UserID, 10, 32)
+		if err != nil {
+			var id uint
+			_, err = fmt.Sscanf(member.UserID, "u%d", &id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid user_id format: %s", member.UserID)
+			}
+			userID = uint64(id)
+		}
+
+		user, err := s.userRepo.UpsertUser(ctx, uint(userID), member.Username, member.IsActive)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
 func mapTeamToDTO(team *model.Team) *dto.Team {
 	members := make([]dto.TeamMember, len(team.Members))
 	for i, member := range team.Members {
@@ -127,3 +376,25 @@ func mapTeamToDTO(team *model.Team) *dto.Team {
 		Members:  members,
 	}
 }
+
+func mapTeamSettingsToDTO(teamName string, settings *model.TeamSettings) *dto.TeamSettings {
+	return &dto.TeamSettings{
+		TeamName:          teamName,
+		MinReviewers:      settings.MinReviewers,
+		MaxReviewers:      settings.MaxReviewers,
+		ReviewerStrategy:  settings.ReviewerStrategy,
+		RequiredApprovals: settings.RequiredApprovals,
+	}
+}
+
+func teamSettingsOrDefault(team *model.Team) model.TeamSettings {
+	if team.Settings != nil {
+		return *team.Settings
+	}
+	return model.TeamSettings{
+		TeamID:           team.ID,
+		MinReviewers:     0,
+		MaxReviewers:     2,
+		ReviewerStrategy: ReviewerStrategyLeastLoaded,
+	}
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
+)
+
+func (s *teamService) AddMembers(ctx context.Context, req dto.AddTeamMembersRequest) (*dto.Team, error) {
+	var result *dto.Team
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.getTeamWithMembers(ctx, req.TeamName)
+		if err != nil {
+			return err
+		}
+		before := mapTeamToDTO(team)
+
+		userIDs, err := s.upsertMembers(ctx, req.Members)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if err := s.teamRepo.AddMember(ctx, team.ID, userID); err != nil {
+				return err
+			}
+		}
+
+		team, err = s.teamRepo.GetByIDWithMembers(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+
+		result = mapTeamToDTO(team)
+		if err := s.outboxRepo.Enqueue(ctx, events.New(events.TypeTeamUpdated, *result)); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.add_members",
+			entityType: model.AuditEntityTeam,
+			entityID:   team.Name,
+			targetIDs:  teamMemberIDs(req.Members),
+			before:     before,
+			after:      result,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RemoveMembers исключает пользователей из команды. Если у них остались OPEN ревью
+// на PR этой команды, удаление отклоняется с HAS_OPEN_REVIEWS, либо, при
+// reassign_open_reviews, ревью распределяются между оставшимися участниками.
+func (s *teamService) RemoveMembers(ctx context.Context, req dto.RemoveTeamMembersRequest) (*dto.RemoveTeamMembersResponse, error) {
+	userIDs := make([]uint, 0, len(req.UserIDs))
+	for _, rawID := range req.UserIDs {
+		userID, err := parseUserID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	var result *dto.RemoveTeamMembersResponse
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.getTeamWithMembers(ctx, req.TeamName)
+		if err != nil {
+			return err
+		}
+
+		before := mapTeamToDTO(team)
+
+		members := make(map[uint]bool, len(team.Members))
+		for _, member := range team.Members {
+			members[member.ID] = true
+		}
+
+		leaving := make(map[uint]bool, len(userIDs))
+		for _, userID := range userIDs {
+			if !members[userID] {
+				return &ServiceError{
+					Code:    dto.ErrorCodeNotMember,
+					Message: fmt.Sprintf("user u%d is not a member of team", userID),
+				}
+			}
+			leaving[userID] = true
+		}
+
+		assignments, err := s.assigner.lockLeavingAssignments(ctx, leaving)
+		if err != nil {
+			return err
+		}
+		assignments = teamAssignments(assignments, team.ID)
+
+		if len(assignments) > 0 && !req.ReassignOpenReviews {
+			return &ServiceError{
+				Code:    dto.ErrorCodeHasOpenReviews,
+				Message: "members have open reviews in team, set reassign_open_reviews to move them",
+			}
+		}
+
+		plan, err := s.assigner.planBulkReassignment(ctx, team, leaving, assignments)
+		if err != nil {
+			return err
+		}
+
+		if err := s.prRepo.ReplaceReviewers(ctx, plan.moves); err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.RemoveMembers(ctx, team.ID, userIDs); err != nil {
+			return err
+		}
+
+		removed := make([]string, 0, len(leaving))
+		remaining := make([]model.User, 0, len(team.Members))
+		for _, member := range team.Members {
+			if leaving[member.ID] {
+				removed = append(removed, fmt.Sprintf("u%d", member.ID))
+				continue
+			}
+			remaining = append(remaining, member)
+		}
+		team.Members = remaining
+
+		result = &dto.RemoveTeamMembersResponse{
+			Team:         *mapTeamToDTO(team),
+			RemovedUsers: removed,
+			PullRequests: plan.reports,
+		}
+
+		published := append(plan.reassignedEvents(), events.New(events.TypeTeamUpdated, result.Team))
+		if err := s.outboxRepo.Enqueue(ctx, published...); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.remove_members",
+			entityType: model.AuditEntityTeam,
+			entityID:   team.Name,
+			targetIDs:  append(removed, reassignmentTargets(plan.reports)...),
+			before:     before,
+			after:      &result.Team,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *teamService) RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*dto.Team, error) {
+	var result *dto.Team
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.getTeamWithMembers(ctx, req.TeamName)
+		if err != nil {
+			return err
+		}
+
+		before := mapTeamToDTO(team)
+
+		if req.NewTeamName != team.Name {
+			exists, err := s.teamRepo.ExistsByName(ctx, req.NewTeamName)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return &ServiceError{
+					Code:    dto.ErrorCodeTeamExists,
+					Message: "team_name already exists",
+				}
+			}
+
+			team.Name = req.NewTeamName
+			if err := s.teamRepo.Update(ctx, team); err != nil {
+				return err
+			}
+
+			if err := s.outboxRepo.Enqueue(ctx, events.New(events.TypeTeamUpdated, *mapTeamToDTO(team))); err != nil {
+				return err
+			}
+		}
+
+		result = mapTeamToDTO(team)
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.rename",
+			entityType: model.AuditEntityTeam,
+			entityID:   req.TeamName,
+			targetIDs:  []string{req.NewTeamName},
+			before:     before,
+			after:      result,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteTeam удаляет команду вместе с членством и настройками. Команду с OPEN PR
+// удалить нельзя: их ревьюверы выбираются из её участников.
+func (s *teamService) DeleteTeam(ctx context.Context, teamName string) error {
+	return s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		team, err := s.getTeamWithMembers(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		status := model.PrStatusOpen
+		openPRs, err := s.prRepo.List(ctx, repository.PullRequestQuery{
+			Filter: repository.PullRequestFilter{
+				Status:   &status,
+				TeamName: team.Name,
+			},
+			Limit: 1,
+		})
+		if err != nil {
+			return err
+		}
+		if len(openPRs) > 0 {
+			return &ServiceError{
+				Code:    dto.ErrorCodeTeamHasOpenPRs,
+				Message: "team has open pull requests",
+			}
+		}
+
+		memberIDs := make([]uint, len(team.Members))
+		for i, member := range team.Members {
+			memberIDs[i] = member.ID
+		}
+
+		if err := s.teamRepo.RemoveMembers(ctx, team.ID, memberIDs); err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.Delete(ctx, team.ID); err != nil {
+			return err
+		}
+
+		err = s.outboxRepo.Enqueue(ctx, events.New(events.TypeTeamDeleted, events.TeamDeleted{
+			TeamName: team.Name,
+		}))
+		if err != nil {
+			return err
+		}
+
+		before := mapTeamToDTO(team)
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.delete",
+			entityType: model.AuditEntityTeam,
+			entityID:   team.Name,
+			targetIDs:  teamMemberIDs(before.Members),
+			before:     before,
+		})
+	})
+}
+
+func (s *teamService) getTeamWithMembers(ctx context.Context, teamName string) (*model.Team, error) {
+	team, err := s.teamRepo.GetByNameWithMembers(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "team not found",
+			}
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+// teamAssignments оставляет только назначения на PR команды teamID.
+func teamAssignments(assignments []repository.ReviewAssignment, teamID uint) []repository.ReviewAssignment {
+	filtered := make([]repository.ReviewAssignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if assignment.TeamID != nil && *assignment.TeamID == teamID {
+			filtered = append(filtered, assignment)
+		}
+	}
+	return filtered
+}
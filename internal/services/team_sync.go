@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/events"
+)
+
+// SyncTeam приводит состав команды к переданному списку: добавляет новых участников,
+// исключает отсутствующих, обновляет is_active и username остальных. Несуществующая
+// команда создаётся. OPEN ревью исключённых и деактивированных на PR команды
+// переназначаются на оставшихся участников. При dry_run изменения только вычисляются.
+func (s *teamService) SyncTeam(ctx context.Context, req dto.SyncTeamRequest) (*dto.SyncTeamResponse, error) {
+	desired := make([]model.User, 0, len(req.Members))
+	index := make(map[uint]int, len(req.Members))
+	for _, member := range req.Members {
+		userID, err := parseUserID(member.UserID)
+		if err != nil {
+			return nil, err
+		}
+		user := model.User{ID: userID, Name: member.Username, IsActive: member.IsActive}
+		if i, ok := index[userID]; ok {
+			desired[i] = user
+			continue
+		}
+		index[userID] = len(desired)
+		desired = append(desired, user)
+	}
+
+	var result *dto.SyncTeamResponse
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		result = &dto.SyncTeamResponse{
+			TeamName:     req.TeamName,
+			DryRun:       req.DryRun,
+			Added:        []string{},
+			Removed:      []string{},
+			Reactivated:  []string{},
+			Deactivated:  []string{},
+			Renamed:      []string{},
+			PullRequests: []dto.PullRequestReassignmentReport{},
+		}
+
+		team, err := s.teamRepo.GetByNameWithMembers(ctx, req.TeamName)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			team = &model.Team{Name: req.TeamName}
+			result.Created = true
+		}
+
+		var before *dto.Team
+		if !result.Created {
+			before = mapTeamToDTO(team)
+		}
+
+		current := make(map[uint]model.User, len(team.Members))
+		for _, member := range team.Members {
+			current[member.ID] = member
+		}
+
+		leaving := make(map[uint]bool)
+		removedIDs := make([]uint, 0)
+		for _, member := range team.Members {
+			if _, ok := index[member.ID]; !ok {
+				leaving[member.ID] = true
+				removedIDs = append(removedIDs, member.ID)
+				result.Removed = append(result.Removed, fmt.Sprintf("u%d", member.ID))
+			}
+		}
+
+		for _, user := range desired {
+			userID := fmt.Sprintf("u%d", user.ID)
+			existing, ok := current[user.ID]
+			if !ok {
+				result.Added = append(result.Added, userID)
+				continue
+			}
+			if !existing.IsActive && user.IsActive {
+				result.Reactivated = append(result.Reactivated, userID)
+			}
+			if existing.IsActive && !user.IsActive {
+				result.Deactivated = append(result.Deactivated, userID)
+				leaving[user.ID] = true
+			}
+			if existing.Name != user.Name {
+				result.Renamed = append(result.Renamed, userID)
+			}
+		}
+
+		plan := &bulkReassignment{}
+		if !result.Created {
+			assignments, err := s.assigner.lockLeavingAssignments(ctx, leaving)
+			if err != nil {
+				return err
+			}
+			assignments = teamAssignments(assignments, team.ID)
+
+			target := &model.Team{ID: team.ID, Name: team.Name, Members: desired, Settings: team.Settings}
+			plan, err = s.assigner.planBulkReassignment(ctx, target, leaving, assignments)
+			if err != nil {
+				return err
+			}
+			result.PullRequests = plan.reports
+		}
+
+		if req.DryRun {
+			return nil
+		}
+
+		if result.Created {
+			if err := s.teamRepo.Create(ctx, team); err != nil {
+				return err
+			}
+		}
+
+		for _, user := range desired {
+			if _, err := s.userRepo.UpsertUser(ctx, user.ID, user.Name, user.IsActive); err != nil {
+				return err
+			}
+		}
+
+		if err := s.prRepo.ReplaceReviewers(ctx, plan.moves); err != nil {
+			return err
+		}
+
+		if err := s.teamRepo.RemoveMembers(ctx, team.ID, removedIDs); err != nil {
+			return err
+		}
+
+		for _, user := range desired {
+			if err := s.teamRepo.AddMember(ctx, team.ID, user.ID); err != nil {
+				return err
+			}
+		}
+
+		team, err = s.teamRepo.GetByIDWithMembers(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+		result.Team = mapTeamToDTO(team)
+
+		changes := len(result.Added) + len(result.Removed) + len(result.Reactivated) +
+			len(result.Deactivated) + len(result.Renamed)
+		if !result.Created && changes == 0 {
+			return nil
+		}
+
+		eventType := events.TypeTeamUpdated
+		if result.Created {
+			eventType = events.TypeTeamCreated
+		}
+		published := append(plan.reassignedEvents(), events.New(eventType, *result.Team))
+		if err := s.outboxRepo.Enqueue(ctx, published...); err != nil {
+			return err
+		}
+
+		targets := make([]string, 0)
+		for _, ids := range [][]string{result.Added, result.Removed, result.Reactivated, result.Deactivated, result.Renamed} {
+			targets = append(targets, ids...)
+		}
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "team.sync",
+			entityType: model.AuditEntityTeam,
+			entityID:   req.TeamName,
+			targetIDs:  append(targets, reassignmentTargets(result.PullRequests)...),
+			before:     before,
+			after:      result.Team,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
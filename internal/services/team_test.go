@@ -0,0 +1,298 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"go-rest-api/internal/api/dto"
+)
+
+func backendTeam(t *testing.T, f *fixture) {
+	f.createTeam(t, "backend", "u1", "u2", "u3")
+}
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestTeamServiceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, f *fixture)
+		call  func(ctx context.Context, f *fixture) error
+		want  dto.ErrorCode
+	}{
+		{
+			name:  "create existing team",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.CreateTeam(ctx, dto.CreateTeamRequest{
+					TeamName: "backend",
+					Members:  []dto.TeamMember{{UserID: "u4", Username: "user-u4", IsActive: true}},
+				})
+				return err
+			},
+			want: dto.ErrorCodeTeamExists,
+		},
+		{
+			name: "get unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.GetTeam(ctx, "missing")
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "get settings of unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.GetSettings(ctx, "missing")
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "update settings of unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.UpdateSettings(ctx, dto.UpdateTeamSettingsRequest{TeamName: "missing", MinReviewers: intPtr(1)})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "update settings with min above max",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.UpdateSettings(ctx, dto.UpdateTeamSettingsRequest{TeamName: "backend", MinReviewers: intPtr(3)})
+				return err
+			},
+			want: dto.ErrorCodeInvalidSettings,
+		},
+		{
+			name:  "update settings with approvals above max",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.UpdateSettings(ctx, dto.UpdateTeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(3)})
+				return err
+			},
+			want: dto.ErrorCodeInvalidSettings,
+		},
+		{
+			name:  "update settings with unknown strategy",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.UpdateSettings(ctx, dto.UpdateTeamSettingsRequest{TeamName: "backend", ReviewerStrategy: strPtr("round_robin")})
+				return err
+			},
+			want: dto.ErrorCodeInvalidSettings,
+		},
+		{
+			name:  "update settings",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.UpdateSettings(ctx, dto.UpdateTeamSettingsRequest{TeamName: "backend", ReviewerStrategy: strPtr("random"), MaxReviewers: intPtr(1)})
+				return err
+			},
+		},
+		{
+			name: "deactivate users of unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.DeactivateUsers(ctx, dto.DeactivateTeamUsersRequest{TeamName: "missing", UserIDs: []string{"u1"}})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "deactivate user from another team",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.DeactivateUsers(ctx, dto.DeactivateTeamUsersRequest{TeamName: "backend", UserIDs: []string{"u9"}})
+				return err
+			},
+			want: dto.ErrorCodeNotMember,
+		},
+		{
+			name: "add members to unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.AddMembers(ctx, dto.AddTeamMembersRequest{
+					TeamName: "missing",
+					Members:  []dto.TeamMember{{UserID: "u4", Username: "user-u4", IsActive: true}},
+				})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "remove members from unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RemoveMembers(ctx, dto.RemoveTeamMembersRequest{TeamName: "missing", UserIDs: []string{"u1"}})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "remove user from another team",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RemoveMembers(ctx, dto.RemoveTeamMembersRequest{TeamName: "backend", UserIDs: []string{"u9"}})
+				return err
+			},
+			want: dto.ErrorCodeNotMember,
+		},
+		{
+			name:  "remove reviewer with open reviews",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RemoveMembers(ctx, dto.RemoveTeamMembersRequest{TeamName: "backend", UserIDs: []string{"u2"}})
+				return err
+			},
+			want: dto.ErrorCodeHasOpenReviews,
+		},
+		{
+			name:  "remove reviewer reassigning open reviews",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RemoveMembers(ctx, dto.RemoveTeamMembersRequest{TeamName: "backend", UserIDs: []string{"u2"}, ReassignOpenReviews: true})
+				return err
+			},
+		},
+		{
+			name: "rename unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RenameTeam(ctx, dto.RenameTeamRequest{TeamName: "missing", NewTeamName: "other"})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "rename to existing name",
+			setup: func(t *testing.T, f *fixture) {
+				backendTeam(t, f)
+				f.createTeam(t, "frontend", "u4")
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.teams.RenameTeam(ctx, dto.RenameTeamRequest{TeamName: "backend", NewTeamName: "frontend"})
+				return err
+			},
+			want: dto.ErrorCodeTeamExists,
+		},
+		{
+			name: "delete unknown team",
+			call: func(ctx context.Context, f *fixture) error {
+				return f.teams.DeleteTeam(ctx, "missing")
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "delete team with open PRs",
+			setup: openPR,
+			call: func(ctx context.Context, f *fixture) error {
+				return f.teams.DeleteTeam(ctx, "backend")
+			},
+			want: dto.ErrorCodeTeamHasOpenPRs,
+		},
+		{
+			name:  "delete team with merged PRs",
+			setup: mergedPR,
+			call: func(ctx context.Context, f *fixture) error {
+				return f.teams.DeleteTeam(ctx, "backend")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			assertCode(t, tt.call(adminContext(), f), tt.want)
+		})
+	}
+}
+
+func TestRemoveMembersReassignsOpenReviews(t *testing.T) {
+	f := newFixture()
+	f.createTeam(t, "backend", "u1", "u2", "u3", "u4")
+	f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "backend", MaxReviewers: intPtr(1)})
+	pr := f.createPR(t, "pr-1", "u1")
+	leaving := pr.AssignedReviewers[0]
+
+	resp, err := f.teams.RemoveMembers(adminContext(), dto.RemoveTeamMembersRequest{
+		TeamName:            "backend",
+		UserIDs:             []string{leaving},
+		ReassignOpenReviews: true,
+	})
+	mustSucceed(t, err)
+
+	if len(resp.Team.Members) != 3 {
+		t.Fatalf("team has %d members, want 3", len(resp.Team.Members))
+	}
+	if len(resp.PullRequests) != 1 || len(resp.PullRequests[0].Replacements) != 1 {
+		t.Fatalf("reports = %+v, want one replacement on pr-1", resp.PullRequests)
+	}
+
+	reviews, err := f.users.GetUserReviews(adminContext(), leaving)
+	mustSucceed(t, err)
+	if len(reviews.PullRequests) != 0 {
+		t.Fatalf("%s still reviews %+v", leaving, reviews.PullRequests)
+	}
+}
+
+func TestSyncTeamDryRunChangesNothing(t *testing.T) {
+	f := newFixture()
+	backendTeam(t, f)
+
+	resp, err := f.teams.SyncTeam(adminContext(), dto.SyncTeamRequest{
+		TeamName: "backend",
+		Members: []dto.TeamMember{
+			{UserID: "u1", Username: "user-u1", IsActive: true},
+			{UserID: "u4", Username: "user-u4", IsActive: true},
+		},
+		DryRun: true,
+	})
+	mustSucceed(t, err)
+	if len(resp.Added) != 1 || len(resp.Removed) != 2 {
+		t.Fatalf("added = %v, removed = %v, want [u4] and [u2 u3]", resp.Added, resp.Removed)
+	}
+
+	team, err := f.teams.GetTeam(adminContext(), "backend")
+	mustSucceed(t, err)
+	if len(team.Members) != 3 {
+		t.Fatalf("dry run changed team members: %+v", team.Members)
+	}
+}
+
+func TestDeactivateUsersKeepsOtherTeamsReviews(t *testing.T) {
+	f := newFixture()
+	f.createTeam(t, "x", "u21", "u22", "u23")
+	f.createTeam(t, "y", "u24", "u25", "u26", "u22")
+	f.updateSettings(t, dto.UpdateTeamSettingsRequest{TeamName: "y", MaxReviewers: intPtr(3)})
+	pr, err := f.prs.CreatePR(adminContext(), dto.CreatePRRequest{
+		PullRequestID:   "pr-700",
+		PullRequestName: "PR pr-700",
+		AuthorID:        "u24",
+		TeamName:        "y",
+	})
+	mustSucceed(t, err)
+
+	resp, err := f.teams.DeactivateUsers(adminContext(), dto.DeactivateTeamUsersRequest{
+		TeamName: "x",
+		UserIDs:  []string{"u22"},
+	})
+	mustSucceed(t, err)
+	if len(resp.PullRequests) != 0 {
+		t.Fatalf("reports = %+v, want none for PRs of team y", resp.PullRequests)
+	}
+
+	reviews, err := f.users.GetUserReviews(adminContext(), "u22")
+	mustSucceed(t, err)
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].PullRequestID != pr.PullRequestID {
+		t.Fatalf("u22 reviews = %+v, want %s kept", reviews.PullRequests, pr.PullRequestID)
+	}
+	reviews, err = f.users.GetUserReviews(adminContext(), "u21")
+	mustSucceed(t, err)
+	if len(reviews.PullRequests) != 0 {
+		t.Errorf("u21 from team x got reviews %+v", reviews.PullRequests)
+	}
+}
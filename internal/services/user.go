@@ -4,41 +4,75 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 
 	"gorm.io/gorm"
 
 	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db"
+	"go-rest-api/internal/db/model"
 	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
 )
 
 type UserService interface {
-	SetIsActive(ctx context.Context, req dto.SetIsActiveRequest) (*dto.User, error)
+	SetIsActive(ctx context.Context, req dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error)
 	GetUserReviews(ctx context.Context, userID string) (*dto.GetUserReviewsResponse, error)
+	AddUnavailability(ctx context.Context, req dto.AddUnavailabilityRequest) (*dto.Unavailability, error)
+	GetUnavailability(ctx context.Context, userID string) (*dto.GetUnavailabilityResponse, error)
+	DeleteUnavailability(ctx context.Context, userID string, id uint) error
 }
 
 type userService struct {
-	db       *gorm.DB
-	userRepo repository.UserRepository
-	prRepo   repository.PullRequestRepository
+	uow        db.UnitOfWork
+	userRepo   repository.UserRepository
+	teamRepo   repository.TeamRepository
+	prRepo     repository.PullRequestRepository
+	outboxRepo repository.OutboxRepository
+	auditRepo  repository.AuditRepository
+	selectors  ReviewerSelectors
+	assigner   *reviewerAssigner
 }
 
-func NewUserService(db *gorm.DB, userRepo repository.UserRepository, prRepo repository.PullRequestRepository) UserService {
+func NewUserService(uow db.UnitOfWork, repos db.Repositories, selectors ReviewerSelectors) UserService {
 	return &userService{
-		db:       db,
-		userRepo: userRepo,
-		prRepo:   prRepo,
+		uow:        uow,
+		userRepo:   repos.Users,
+		teamRepo:   repos.Teams,
+		prRepo:     repos.PullRequests,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  selectors,
+		assigner:   newReviewerAssigner(repos, selectors),
 	}
 }
 
-func (s *userService) SetIsActive(ctx context.Context, req dto.SetIsActiveRequest) (*dto.User, error) {
+// bind возвращает копию сервиса, работающую через репозитории транзакции.
+func (s *userService) bind(repos db.Repositories) *userService {
+	return &userService{
+		uow:        s.uow,
+		userRepo:   repos.Users,
+		teamRepo:   repos.Teams,
+		prRepo:     repos.PullRequests,
+		outboxRepo: repos.Outbox,
+		auditRepo:  repos.Audit,
+		selectors:  s.selectors,
+		assigner:   newReviewerAssigner(repos, s.selectors),
+	}
+}
+
+func (s *userService) SetIsActive(ctx context.Context, req dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, error) {
 	userID, err := parseUserID(req.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	var result *dto.User
-	err = s.db.Transaction(func(tx *gorm.DB) error {
+	var result *dto.SetIsActiveResponse
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
 		user, err := s.userRepo.GetByIDWithTeams(ctx, userID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -49,24 +83,36 @@ func (s *userService) SetIsActive(ctx context.Context, req dto.SetIsActiveReques
 			}
 			return err
 		}
+		before := mapUserToDTO(user)
 
 		user.IsActive = req.IsActive
 		if err := s.userRepo.Update(ctx, user); err != nil {
 			return err
 		}
 
-		teamName := ""
-		if len(user.Teams) > 0 {
-			teamName = user.Teams[0].Name
+		result = &dto.SetIsActiveResponse{
+			User: mapUserToDTO(user),
 		}
 
-		result = &dto.User{
-			UserID:   req.UserID,
-			Username: user.Name,
-			TeamName: teamName,
-			IsActive: user.IsActive,
+		var targets []string
+		if !req.IsActive && req.ReassignOpenReviews {
+			result.Reassignment, err = s.reassignOpenReviews(ctx, user)
+			if err != nil {
+				return err
+			}
+			for _, reassignment := range result.Reassignment.Reassigned {
+				targets = append(targets, reassignment.PullRequestID, reassignment.NewUserID)
+			}
 		}
-		return nil
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "user.set_is_active",
+			entityType: model.AuditEntityUser,
+			entityID:   result.User.UserID,
+			targetIDs:  targets,
+			before:     &before,
+			after:      &result.User,
+		})
 	})
 
 	if err != nil {
@@ -76,6 +122,92 @@ func (s *userService) SetIsActive(ctx context.Context, req dto.SetIsActiveReques
 	return result, nil
 }
 
+// reassignOpenReviews переназначает все OPEN ревью пользователя тем же планом,
+// что и массовые операции над командой: ревью каждого PR распределяются между
+// участниками его команды, а если она не задана - основной команды пользователя.
+// PR блокируются до чтения назначений (lockLeavingAssignments). PR без подходящего
+// кандидата остаются как есть и попадают в NoCandidate.
+func (s *userService) reassignOpenReviews(ctx context.Context, user *model.User) (*dto.ReassignmentSummary, error) {
+	leaving := map[uint]bool{user.ID: true}
+
+	assignments, err := s.assigner.lockLeavingAssignments(ctx, leaving)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallbackTeamID *uint
+	if primary := user.PrimaryTeam(); primary != nil {
+		fallbackTeamID = &primary.ID
+	}
+	byTeam := make(map[uint][]repository.ReviewAssignment)
+	for _, assignment := range assignments {
+		teamID := assignment.TeamID
+		if teamID == nil {
+			teamID = fallbackTeamID
+		}
+		if teamID != nil {
+			byTeam[*teamID] = append(byTeam[*teamID], assignment)
+		}
+	}
+
+	reports := make(map[string]dto.PullRequestReassignmentReport)
+	published := make([]events.Event, 0)
+	for _, teamID := range slices.Sorted(maps.Keys(byTeam)) {
+		team, err := s.teamRepo.GetByIDWithMembers(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+
+		plan, err := s.assigner.planBulkReassignment(ctx, team, leaving, byTeam[teamID])
+		if err != nil {
+			return nil, err
+		}
+		if err := s.prRepo.ReplaceReviewers(ctx, plan.moves); err != nil {
+			return nil, err
+		}
+
+		published = append(published, plan.reassignedEvents()...)
+		for _, report := range plan.reports {
+			reports[report.PullRequestID] = report
+		}
+	}
+
+	summary := &dto.ReassignmentSummary{
+		Reassigned:  []dto.ReviewReassignment{},
+		NoCandidate: []string{},
+	}
+	for _, prID := range assignmentPRIDs(assignments) {
+		id := fmt.Sprintf("pr-%d", prID)
+		report, ok := reports[id]
+		if !ok || len(report.Replacements) == 0 {
+			summary.NoCandidate = append(summary.NoCandidate, id)
+			continue
+		}
+		for _, replacement := range report.Replacements {
+			summary.Reassigned = append(summary.Reassigned, dto.ReviewReassignment{
+				PullRequestID: id,
+				OldUserID:     replacement.OldUserID,
+				NewUserID:     replacement.NewUserID,
+			})
+		}
+	}
+
+	if err := s.outboxRepo.Enqueue(ctx, published...); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// assignmentPRIDs возвращает id PR из assignments по возрастанию, без повторов.
+func assignmentPRIDs(assignments []repository.ReviewAssignment) []uint {
+	ids := make([]uint, 0, len(assignments))
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.PrID)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
 func (s *userService) GetUserReviews(ctx context.Context, userID string) (*dto.GetUserReviewsResponse, error) {
 	uid, err := parseUserID(userID)
 	if err != nil {
@@ -88,13 +220,8 @@ func (s *userService) GetUserReviews(ctx context.Context, userID string) (*dto.G
 	}
 
 	prList := make([]dto.PullRequestShort, len(prs))
-	for i, pr := range prs {
-		prList[i] = dto.PullRequestShort{
-			PullRequestID:   fmt.Sprintf("pr-%d", pr.ID),
-			PullRequestName: pr.Title,
-			AuthorID:        fmt.Sprintf("u%d", pr.AuthorID),
-			Status:          dto.PullRequestStatus(pr.Status),
-		}
+	for i := range prs {
+		prList[i] = mapPullRequestToShortDTO(&prs[i])
 	}
 
 	return &dto.GetUserReviewsResponse{
@@ -103,6 +230,133 @@ func (s *userService) GetUserReviews(ctx context.Context, userID string) (*dto.G
 	}, nil
 }
 
+func (s *userService) AddUnavailability(ctx context.Context, req dto.AddUnavailabilityRequest) (*dto.Unavailability, error) {
+	userID, err := parseUserID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, &ServiceError{
+			Code:    dto.ErrorCodeInvalidPeriod,
+			Message: "ends_at must be after starts_at",
+		}
+	}
+
+	var result dto.Unavailability
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &ServiceError{
+					Code:    dto.ErrorCodeNotFound,
+					Message: "user not found",
+				}
+			}
+			return err
+		}
+
+		period := &model.UserUnavailability{
+			UserID:   userID,
+			StartsAt: req.StartsAt,
+			EndsAt:   req.EndsAt,
+			Reason:   req.Reason,
+		}
+		if err := s.userRepo.AddUnavailability(ctx, period); err != nil {
+			return err
+		}
+
+		result = mapUnavailabilityToDTO(period)
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "user.add_unavailability",
+			entityType: model.AuditEntityUser,
+			entityID:   result.UserID,
+			after:      &result,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *userService) GetUnavailability(ctx context.Context, userID string) (*dto.GetUnavailabilityResponse, error) {
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.userRepo.ListUnavailability(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.GetUnavailabilityResponse{
+		UserID:  userID,
+		Periods: make([]dto.Unavailability, len(periods)),
+	}
+	for i := range periods {
+		result.Periods[i] = mapUnavailabilityToDTO(&periods[i])
+	}
+
+	return result, nil
+}
+
+func (s *userService) DeleteUnavailability(ctx context.Context, userID string, id uint) error {
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context, repos db.Repositories) error {
+		s := s.bind(repos)
+
+		periods, err := s.userRepo.ListUnavailability(ctx, uid)
+		if err != nil {
+			return err
+		}
+		var before *dto.Unavailability
+		for i := range periods {
+			if periods[i].ID == id {
+				period := mapUnavailabilityToDTO(&periods[i])
+				before = &period
+			}
+		}
+
+		deleted, err := s.userRepo.DeleteUnavailability(ctx, uid, id)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return &ServiceError{
+				Code:    dto.ErrorCodeNotFound,
+				Message: "unavailability period not found",
+			}
+		}
+
+		return recordAudit(ctx, s.auditRepo, auditRecord{
+			operation:  "user.delete_unavailability",
+			entityType: model.AuditEntityUser,
+			entityID:   fmt.Sprintf("u%d", uid),
+			before:     before,
+		})
+	})
+}
+
+func mapUnavailabilityToDTO(period *model.UserUnavailability) dto.Unavailability {
+	return dto.Unavailability{
+		ID:       period.ID,
+		UserID:   fmt.Sprintf("u%d", period.UserID),
+		StartsAt: period.StartsAt,
+		EndsAt:   period.EndsAt,
+		Reason:   period.Reason,
+	}
+}
+
 func parseUserID(userID string) (uint, error) {
 	uid, err := strconv.ParseUint(userID, 10, 32)
 	if err != nil {
@@ -115,3 +369,25 @@ func parseUserID(userID string) (uint, error) {
 	}
 	return uint(uid), nil
 }
+
+// mapUserToDTO ожидает загруженные Memberships: team_name - основная команда,
+// teams - все команды пользователя, начиная с основной.
+func mapUserToDTO(user *model.User) dto.User {
+	teams := make([]string, len(user.Memberships))
+	for i, membership := range user.Memberships {
+		teams[i] = membership.Team.Name
+	}
+
+	teamName := ""
+	if primary := user.PrimaryTeam(); primary != nil {
+		teamName = primary.Name
+	}
+
+	return dto.User{
+		UserID:   fmt.Sprintf("u%d", user.ID),
+		Username: user.Name,
+		TeamName: teamName,
+		Teams:    teams,
+		IsActive: user.IsActive,
+	}
+}
@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/events"
+)
+
+func TestUserServiceErrors(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, f *fixture)
+		call  func(ctx context.Context, f *fixture) error
+		want  dto.ErrorCode
+	}{
+		{
+			name: "set is_active of unknown user",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.users.SetIsActive(ctx, dto.SetIsActiveRequest{UserID: "u9", IsActive: false})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "set is_active",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.users.SetIsActive(ctx, dto.SetIsActiveRequest{UserID: "u2", IsActive: false})
+				return err
+			},
+		},
+		{
+			name:  "add period ending before start",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.users.AddUnavailability(ctx, dto.AddUnavailabilityRequest{UserID: "u1", StartsAt: now, EndsAt: now.Add(-time.Hour)})
+				return err
+			},
+			want: dto.ErrorCodeInvalidPeriod,
+		},
+		{
+			name: "add period to unknown user",
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.users.AddUnavailability(ctx, dto.AddUnavailabilityRequest{UserID: "u9", StartsAt: now, EndsAt: now.Add(time.Hour)})
+				return err
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name:  "add period",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				_, err := f.users.AddUnavailability(ctx, dto.AddUnavailabilityRequest{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour)})
+				return err
+			},
+		},
+		{
+			name:  "delete unknown period",
+			setup: backendTeam,
+			call: func(ctx context.Context, f *fixture) error {
+				return f.users.DeleteUnavailability(ctx, "u1", 42)
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+		{
+			name: "delete period of another user",
+			setup: func(t *testing.T, f *fixture) {
+				backendTeam(t, f)
+				_, err := f.users.AddUnavailability(adminContext(), dto.AddUnavailabilityRequest{UserID: "u1", StartsAt: now, EndsAt: now.Add(time.Hour)})
+				mustSucceed(t, err)
+			},
+			call: func(ctx context.Context, f *fixture) error {
+				return f.users.DeleteUnavailability(ctx, "u2", 1)
+			},
+			want: dto.ErrorCodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture()
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			assertCode(t, tt.call(adminContext(), f), tt.want)
+		})
+	}
+}
+
+func TestSetIsActiveReassignsOpenReviews(t *testing.T) {
+	f := newFixture()
+	openPR(t, f)
+	f.createPR(t, "pr-2", "u3")
+	_, err := f.teams.AddMembers(adminContext(), dto.AddTeamMembersRequest{
+		TeamName: "backend",
+		Members:  []dto.TeamMember{{UserID: "u4", Username: "user-u4", IsActive: true}},
+	})
+	mustSucceed(t, err)
+
+	// Для части PR u2 может не найтись замены: такие попадают в NoCandidate
+	// и остаются за ним.
+	resp, err := f.users.SetIsActive(adminContext(), dto.SetIsActiveRequest{
+		UserID:              "u2",
+		IsActive:            false,
+		ReassignOpenReviews: true,
+	})
+	mustSucceed(t, err)
+
+	if resp.User.IsActive {
+		t.Fatal("user is still active")
+	}
+	if resp.Reassignment == nil {
+		t.Fatal("reassignment summary is missing")
+	}
+	for _, moved := range resp.Reassignment.Reassigned {
+		if moved.OldUserID != "u2" || moved.NewUserID == "u2" {
+			t.Fatalf("unexpected reassignment %+v", moved)
+		}
+	}
+
+	reviews, err := f.users.GetUserReviews(adminContext(), "u2")
+	mustSucceed(t, err)
+	if got, want := len(reviews.PullRequests), len(resp.Reassignment.NoCandidate); got != want {
+		t.Fatalf("u2 still reviews %d PRs, want %d without candidate", got, want)
+	}
+
+	reassigned := 0
+	for _, msg := range f.store.OutboxMessages() {
+		if msg.EventType == string(events.TypeReviewerReassigned) {
+			reassigned++
+		}
+	}
+	if reassigned != len(resp.Reassignment.Reassigned) {
+		t.Fatalf("outbox has %d reviewer.reassigned events, want %d", reassigned, len(resp.Reassignment.Reassigned))
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+
+	"go-rest-api/internal/api/dto"
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+)
+
+type WebhookSubscriptionService interface {
+	CreateSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*dto.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) ([]dto.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+}
+
+type webhookSubscriptionService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookSubscriptionService(webhookRepo repository.WebhookRepository) WebhookSubscriptionService {
+	return &webhookSubscriptionService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (s *webhookSubscriptionService) CreateSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*dto.WebhookSubscription, error) {
+	sub := &model.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: model.EventTypes(req.EventTypes),
+		CreatedAt:  timeNow(),
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	result := mapWebhookSubscriptionToDTO(sub)
+	return &result, nil
+}
+
+func (s *webhookSubscriptionService) GetSubscriptions(ctx context.Context) ([]dto.WebhookSubscription, error) {
+	subs, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.WebhookSubscription, len(subs))
+	for i := range subs {
+		result[i] = mapWebhookSubscriptionToDTO(&subs[i])
+	}
+	return result, nil
+}
+
+func (s *webhookSubscriptionService) DeleteSubscription(ctx context.Context, id uint) error {
+	deleted, err := s.webhookRepo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    dto.ErrorCodeNotFound,
+			Message: "subscription not found",
+		}
+	}
+	return nil
+}
+
+func mapWebhookSubscriptionToDTO(sub *model.WebhookSubscription) dto.WebhookSubscription {
+	return dto.WebhookSubscription{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: []string(sub.EventTypes),
+		CreatedAt:  sub.CreatedAt,
+	}
+}
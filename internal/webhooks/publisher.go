@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/db/repository"
+	"go-rest-api/internal/events"
+)
+
+// Publisher - получатель событий outbox, который сохраняет по доставке на каждую
+// подписку, которой интересно событие. Отправкой занимается Worker.
+type Publisher struct {
+	repo repository.WebhookRepository
+}
+
+func NewPublisher(repo repository.WebhookRepository) *Publisher {
+	return &Publisher{
+		repo: repo,
+	}
+}
+
+func (p *Publisher) Name() string {
+	return "webhooks"
+}
+
+func (p *Publisher) Publish(ctx context.Context, ev events.Event) error {
+	subs, err := p.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := buildDeliveries(subs, []events.Event{ev}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	return p.repo.CreateDeliveries(ctx, deliveries)
+}
+
+func buildDeliveries(subs []model.WebhookSubscription, evs []events.Event, now time.Time) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, 0)
+	for _, ev := range evs {
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sub := range subs {
+			if !sub.EventTypes.Contains(string(ev.Type)) {
+				continue
+			}
+			deliveries = append(deliveries, model.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				EventType:      string(ev.Type),
+				Payload:        string(payload),
+				Status:         model.WebhookDeliveryPending,
+				NextAttemptAt:  now,
+				CreatedAt:      now,
+			})
+		}
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/db/model"
+	"go-rest-api/internal/events"
+)
+
+func TestBuildDeliveries_FiltersByEventType(t *testing.T) {
+	subs := []model.WebhookSubscription{
+		{ID: 1, EventTypes: model.EventTypes{"pr.created", "pr.merged"}},
+		{ID: 2, EventTypes: model.EventTypes{"reviewer.assigned"}},
+	}
+	evs := []events.Event{
+		events.New(events.TypePRCreated, map[string]string{"pull_request_id": "pr-1"}),
+		events.New(events.TypeReviewerAssigned, events.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "u2"}),
+		events.New(events.TypeReviewerReassigned, events.ReviewerReassigned{PullRequestID: "pr-1"}),
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	deliveries, err := buildDeliveries(subs, evs, now)
+	if err != nil {
+		t.Fatalf("buildDeliveries: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("deliveries = %d, want 2", len(deliveries))
+	}
+
+	if d := deliveries[0]; d.SubscriptionID != 1 || d.EventType != "pr.created" {
+		t.Errorf("deliveries[0] = %+v", d)
+	}
+	if d := deliveries[1]; d.SubscriptionID != 2 || d.EventType != "reviewer.assigned" {
+		t.Errorf("deliveries[1] = %+v", d)
+	}
+
+	var payload struct {
+		Event string                  `json:"event"`
+		Data  events.ReviewerAssigned `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(deliveries[1].Payload), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Event != "reviewer.assigned" || payload.Data.ReviewerID != "u2" {
+		t.Errorf("payload = %+v", payload)
+	}
+	for _, d := range deliveries {
+		if d.Status != model.WebhookDeliveryPending || !d.NextAttemptAt.Equal(now) {
+			t.Errorf("delivery %+v must be pending and due now", d)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/model"
+)
+
+// Store - часть WebhookRepository, которая нужна Worker.
+type Store interface {
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id uint64, attempts int, at time.Time) error
+	ScheduleRetry(ctx context.Context, id uint64, attempts int, next time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id uint64, attempts int, lastError string) error
+}
+
+// Worker периодически отправляет накопившиеся доставки. Состояние хранится в БД,
+// поэтому недоставленные события переживают перезапуск.
+type Worker struct {
+	store  Store
+	client *http.Client
+	cfg    config.OutgoingWebhooks
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+func NewWorker(store Store, cfg config.OutgoingWebhooks, logger *slog.Logger) *Worker {
+	return &Worker{
+		store:  store,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		logger: logger,
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run обрабатывает доставки до отмены ctx.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil {
+			w.logger.Error("failed to process webhook deliveries", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue делает одну попытку для каждой доставки, время которой наступило,
+// и возвращает число успешных отправок.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := w.store.ClaimDueDeliveries(ctx, w.now(), w.cfg.ClaimLease, w.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		d := &deliveries[i]
+		attempts := d.Attempts + 1
+
+		sendErr := w.send(ctx, d)
+		if sendErr == nil {
+			if err := w.store.MarkDelivered(ctx, d.ID, attempts, w.now()); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		w.logger.Warn("webhook delivery failed",
+			"delivery_id", d.ID, "url", d.Subscription.URL, "attempt", attempts, "error", sendErr)
+
+		if attempts >= w.cfg.MaxAttempts {
+			err = w.store.MarkFailed(ctx, d.ID, attempts, sendErr.Error())
+		} else {
+			err = w.store.ScheduleRetry(ctx, d.ID, attempts, w.now().Add(w.Backoff(attempts)), sendErr.Error())
+		}
+		if err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+// Backoff - задержка перед следующей попыткой после attempt неудачных.
+func (w *Worker) Backoff(attempt int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempt && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.cfg.MaxBackoff)
+}
+
+func (w *Worker) send(ctx context.Context, d *model.WebhookDelivery) error {
+	body := []byte(d.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Subscription.Secret, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-rest-api/internal/config"
+	"go-rest-api/internal/db/model"
+)
+
+// memoryStore хранит доставки в памяти вместо webhook_deliveries.
+type memoryStore struct {
+	mu         sync.Mutex
+	deliveries map[uint64]*model.WebhookDelivery
+}
+
+func newMemoryStore(deliveries ...model.WebhookDelivery) *memoryStore {
+	s := &memoryStore{deliveries: map[uint64]*model.WebhookDelivery{}}
+	for i := range deliveries {
+		d := deliveries[i]
+		s.deliveries[d.ID] = &d
+	}
+	return s
+}
+
+func (s *memoryStore) ClaimDueDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []model.WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == model.WebhookDeliveryPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, *d)
+			d.NextAttemptAt = now.Add(lease)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryStore) MarkDelivered(_ context.Context, id uint64, attempts int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.deliveries[id]
+	d.Status, d.Attempts, d.DeliveredAt, d.LastError = model.WebhookDeliveryDelivered, attempts, &at, ""
+	return nil
+}
+
+func (s *memoryStore) ScheduleRetry(_ context.Context, id uint64, attempts int, next time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.deliveries[id]
+	d.Attempts, d.NextAttemptAt, d.LastError = attempts, next, lastError
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(_ context.Context, id uint64, attempts int, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.deliveries[id]
+	d.Status, d.Attempts, d.LastError = model.WebhookDeliveryFailed, attempts, lastError
+	return nil
+}
+
+func (s *memoryStore) get(id uint64) model.WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.deliveries[id]
+}
+
+// receiver - локальный подписчик, отвечающий ошибкой на первые failures запросов.
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	if len(r.requests) <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestWorker(store Store, clock *testClock, maxAttempts int) *Worker {
+	w := NewWorker(store, config.OutgoingWebhooks{
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      time.Second,
+		BatchSize:    10,
+		MaxAttempts:  maxAttempts,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   4 * time.Second,
+		ClaimLease:   time.Minute,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	w.now = clock.Now
+	return w
+}
+
+func newDelivery(url string) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:        1,
+		EventType: "pr.created",
+		Payload:   `{"event":"pr.created","data":{"pull_request_id":"pr-1"}}`,
+		Status:    model.WebhookDeliveryPending,
+		Subscription: model.WebhookSubscription{
+			URL:    url,
+			Secret: "s3cret",
+		},
+	}
+}
+
+func TestWorker_DeliversSignedPayload(t *testing.T) {
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	clock := &testClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	store := newMemoryStore(newDelivery(server.URL))
+	worker := newTestWorker(store, clock, 3)
+
+	delivered, err := worker.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if delivered != 1 {
+		t.Fatalf("delivered = %d, want 1", delivered)
+	}
+
+	req, body := recv.requests[0], recv.bodies[0]
+	if got := req.Header.Get(HeaderEvent); got != "pr.created" {
+		t.Errorf("%s = %q, want pr.created", HeaderEvent, got)
+	}
+	if got := req.Header.Get(HeaderDelivery); got != "1" {
+		t.Errorf("%s = %q, want 1", HeaderDelivery, got)
+	}
+	if got, want := req.Header.Get(HeaderSignature), Sign("s3cret", body); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+
+	d := store.get(1)
+	if d.Status != model.WebhookDeliveryDelivered || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Errorf("delivery = %+v, want delivered after 1 attempt", d)
+	}
+}
+
+func TestWorker_RetriesWithBackoff(t *testing.T) {
+	recv := &receiver{failures: 2}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := &testClock{now: start}
+	store := newMemoryStore(newDelivery(server.URL))
+	worker := newTestWorker(store, clock, 5)
+	ctx := context.Background()
+
+	if _, err := worker.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	d := store.get(1)
+	if d.Status != model.WebhookDeliveryPending || d.Attempts != 1 || !d.NextAttemptAt.Equal(start.Add(time.Second)) {
+		t.Fatalf("after first failure: %+v", d)
+	}
+
+	// До наступления next_attempt_at доставка не повторяется.
+	if _, err := worker.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if len(recv.requests) != 1 {
+		t.Fatalf("requests = %d, want 1 before backoff elapsed", len(recv.requests))
+	}
+
+	clock.now = start.Add(time.Second)
+	if _, err := worker.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	d = store.get(1)
+	if d.Attempts != 2 || !d.NextAttemptAt.Equal(clock.now.Add(2*time.Second)) {
+		t.Fatalf("after second failure: %+v", d)
+	}
+
+	clock.now = d.NextAttemptAt
+	delivered, err := worker.ProcessDue(ctx)
+	if err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if delivered != 1 {
+		t.Fatalf("delivered = %d, want 1", delivered)
+	}
+	if d = store.get(1); d.Status != model.WebhookDeliveryDelivered || d.Attempts != 3 {
+		t.Errorf("delivery = %+v, want delivered after 3 attempts", d)
+	}
+}
+
+func TestWorker_GivesUpAfterMaxAttempts(t *testing.T) {
+	recv := &receiver{failures: 100}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	clock := &testClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	store := newMemoryStore(newDelivery(server.URL))
+	worker := newTestWorker(store, clock, 2)
+
+	for range 3 {
+		if _, err := worker.ProcessDue(context.Background()); err != nil {
+			t.Fatalf("ProcessDue: %v", err)
+		}
+		clock.now = clock.now.Add(time.Hour)
+	}
+
+	d := store.get(1)
+	if d.Status != model.WebhookDeliveryFailed || d.Attempts != 2 {
+		t.Errorf("delivery = %+v, want failed after 2 attempts", d)
+	}
+	if len(recv.requests) != 2 {
+		t.Errorf("requests = %d, want 2", len(recv.requests))
+	}
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	worker := newTestWorker(newMemoryStore(), &testClock{}, 10)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second}
+	for i, w := range want {
+		if got := worker.Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
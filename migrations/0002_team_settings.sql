@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    min_reviewers INTEGER NOT NULL DEFAULT 0,
+    max_reviewers INTEGER NOT NULL DEFAULT 2,
+    reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'least_loaded',
+    CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers)
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS team_settings;
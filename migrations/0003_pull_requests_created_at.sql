@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests (author_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (status);
+
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pull_requests_status;
+DROP INDEX IF EXISTS idx_pull_requests_author_id;
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS merged_at TIMESTAMPTZ;
+
+UPDATE pull_requests SET merged_at = updated_at WHERE status = 'MERGED' AND merged_at IS NULL;
+
+
+-- +goose Down
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS merged_at;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS updated_at;
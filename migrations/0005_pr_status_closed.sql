@@ -0,0 +1,16 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
+
+
+-- +goose Down
+UPDATE pull_requests SET status = 'OPEN' WHERE status = 'CLOSED';
+
+ALTER TYPE pr_status RENAME TO pr_status_old;
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+
+ALTER TABLE pull_requests ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN status TYPE pr_status USING status::text::pr_status;
+ALTER TABLE pull_requests ALTER COLUMN status SET DEFAULT 'OPEN';
+
+DROP TYPE pr_status_old;
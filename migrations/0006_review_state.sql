@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TYPE review_state AS ENUM ('PENDING', 'APPROVED', 'CHANGES_REQUESTED');
+
+ALTER TABLE pull_request_reviewer ADD COLUMN IF NOT EXISTS state review_state NOT NULL DEFAULT 'PENDING';
+ALTER TABLE pull_request_reviewer ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+
+-- +goose Down
+ALTER TABLE team_settings DROP COLUMN IF EXISTS required_approvals;
+
+ALTER TABLE pull_request_reviewer DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE pull_request_reviewer DROP COLUMN IF EXISTS state;
+
+DROP TYPE IF EXISTS review_state;
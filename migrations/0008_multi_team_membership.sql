@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE user_team ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE user_team ut
+SET is_primary = TRUE
+WHERE ut.team_id = (SELECT MIN(team_id) FROM user_team WHERE user_id = ut.user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_team_primary ON user_team (user_id) WHERE is_primary;
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS team_id INTEGER REFERENCES teams(id) ON DELETE SET NULL;
+
+UPDATE pull_requests pr
+SET team_id = ut.team_id
+FROM user_team ut
+WHERE ut.user_id = pr.author_id AND ut.is_primary AND pr.team_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_team_id ON pull_requests (team_id);
+
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pull_requests_team_id;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS team_id;
+
+DROP INDEX IF EXISTS idx_user_team_primary;
+ALTER TABLE user_team DROP COLUMN IF EXISTS is_primary;
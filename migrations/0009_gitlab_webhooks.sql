@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS gitlab_users (
+    username VARCHAR(255) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS gitlab_webhook_events (
+    event_uuid VARCHAR(64) PRIMARY KEY,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS gitlab_webhook_events;
+DROP TABLE IF EXISTS gitlab_users;
@@ -0,0 +1,44 @@
+-- +goose Up
+ALTER TABLE pull_requests ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN status TYPE VARCHAR(16) USING status::text;
+ALTER TABLE pull_requests ALTER COLUMN status SET DEFAULT 'OPEN';
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED', 'CLOSED'));
+
+ALTER TABLE pull_request_reviewer ALTER COLUMN state DROP DEFAULT;
+ALTER TABLE pull_request_reviewer ALTER COLUMN state TYPE VARCHAR(32) USING state::text;
+ALTER TABLE pull_request_reviewer ALTER COLUMN state SET DEFAULT 'PENDING';
+ALTER TABLE pull_request_reviewer ADD CONSTRAINT pull_request_reviewer_state_check CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED'));
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+ALTER TABLE webhook_deliveries ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN status TYPE VARCHAR(16) USING status::text;
+ALTER TABLE webhook_deliveries ALTER COLUMN status SET DEFAULT 'PENDING';
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED'));
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+
+DROP TYPE IF EXISTS pr_status;
+DROP TYPE IF EXISTS review_state;
+DROP TYPE IF EXISTS webhook_delivery_status;
+
+
+-- +goose Down
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED', 'CLOSED');
+CREATE TYPE review_state AS ENUM ('PENDING', 'APPROVED', 'CHANGES_REQUESTED');
+CREATE TYPE webhook_delivery_status AS ENUM ('PENDING', 'DELIVERED', 'FAILED');
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE webhook_deliveries ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN status TYPE webhook_delivery_status USING status::webhook_delivery_status;
+ALTER TABLE webhook_deliveries ALTER COLUMN status SET DEFAULT 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+
+ALTER TABLE pull_request_reviewer DROP CONSTRAINT IF EXISTS pull_request_reviewer_state_check;
+ALTER TABLE pull_request_reviewer ALTER COLUMN state DROP DEFAULT;
+ALTER TABLE pull_request_reviewer ALTER COLUMN state TYPE review_state USING state::review_state;
+ALTER TABLE pull_request_reviewer ALTER COLUMN state SET DEFAULT 'PENDING';
+
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE pull_requests ALTER COLUMN status TYPE pr_status USING status::pr_status;
+ALTER TABLE pull_requests ALTER COLUMN status SET DEFAULT 'OPEN';
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS external_pull_requests (
+    provider VARCHAR(16) NOT NULL,
+    repository VARCHAR(255) NOT NULL,
+    number BIGINT NOT NULL,
+    pr_id INTEGER NOT NULL UNIQUE REFERENCES pull_requests(id) ON DELETE CASCADE,
+    PRIMARY KEY (provider, repository, number)
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS external_pull_requests;
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lease_token VARCHAR(64) NOT NULL DEFAULT '';
+
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS lease_token;
@@ -0,0 +1,8 @@
+// Package migrations встраивает SQL-миграции в бинарник: файлы в корне - схема
+// Postgres, в каталоге sqlite - схема SQLite.
+package migrations
+
+import "embed"
+
+//go:embed *.sql sqlite/*.sql
+var FS embed.FS
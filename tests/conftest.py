@@ -1,3 +1,4 @@
+import random
 import uuid
 import pytest
 import httpx
@@ -8,11 +9,36 @@ def base_url():
     return "http://localhost:8080"
 
 
+ADMIN_TOKEN = "dev-admin-token"
+USER_TOKEN = "dev-user-token"
+USER_TOKEN_SUBJECT = "u1"
+
+
 @pytest.fixture()
 def client(base_url):
+    with httpx.Client(base_url=base_url, headers={"Authorization": f"Bearer {ADMIN_TOKEN}"}) as c:
+        yield c
+
+
+@pytest.fixture()
+def user_client(base_url):
+    with httpx.Client(base_url=base_url, headers={"Authorization": f"Bearer {USER_TOKEN}"}) as c:
+        yield c
+
+
+@pytest.fixture()
+def anonymous_client(base_url):
     with httpx.Client(base_url=base_url) as c:
         yield c
 
 
 def get_random_name():
     return str(uuid.uuid4())
+
+
+def get_random_user_id():
+    return f"u{random.randint(100_000, 2_000_000_000)}"
+
+
+def get_random_pr_id():
+    return f"pr-{random.randint(100_000, 2_000_000_000)}"
@@ -0,0 +1,76 @@
+import httpx
+
+from conftest import get_random_name
+from test_pull_request import create_team, create_pr
+
+
+def test_audit_records_pr_lifecycle(client: httpx.Client):
+    team_name, user_ids = create_team(client, 4)
+    response = create_pr(client, user_ids[0])
+    assert response.status_code == 201
+    pr = response.json()["pr"]
+    old_reviewer = pr["assigned_reviewers"][0]
+
+    response = client.post("/pullRequest/reassign", json={
+        "pull_request_id": pr["pull_request_id"],
+        "old_user_id": old_reviewer,
+    })
+    assert response.status_code == 200
+    new_reviewer = response.json()["replaced_by"]
+
+    response = client.get("/audit", params={"entity_id": pr["pull_request_id"]})
+    assert response.status_code == 200
+    entries = response.json()["entries"]
+    assert [e["operation"] for e in entries] == ["pr.reassign", "pr.create"]
+
+    reassign, create = entries
+    assert reassign["actor"] == "admin"
+    assert reassign["entity_type"] == "pull_request"
+    assert set(reassign["target_ids"]) == {old_reviewer, new_reviewer}
+    reviewers = reassign["diff"]["assigned_reviewers"]
+    assert old_reviewer in reviewers["before"]
+    assert new_reviewer in reviewers["after"]
+
+    assert create["diff"]["status"] == {"before": None, "after": "OPEN"}
+
+    response = client.get("/audit", params={"entity_id": new_reviewer, "entity_type": "pull_request"})
+    assert response.status_code == 200
+    assert reassign["id"] in [e["id"] for e in response.json()["entries"]]
+
+
+def test_audit_records_team_rename(client: httpx.Client):
+    team_name, _ = create_team(client, 2)
+    new_name = get_random_name()
+    response = client.patch("/team/rename", json={"team_name": team_name, "new_team_name": new_name})
+    assert response.status_code == 200
+
+    response = client.get("/audit", params={"entity_id": new_name, "entity_type": "team"})
+    assert response.status_code == 200
+    entries = response.json()["entries"]
+    assert [e["operation"] for e in entries] == ["team.rename"]
+    assert entries[0]["entity_id"] == team_name
+    assert entries[0]["diff"] == {"team_name": {"before": team_name, "after": new_name}}
+
+
+def test_audit_pagination_and_filters(client: httpx.Client):
+    team_name, user_ids = create_team(client, 3)
+    for _ in range(3):
+        assert create_pr(client, user_ids[0]).status_code == 201
+
+    response = client.get("/audit", params={"entity_id": user_ids[0], "actor": "admin", "limit": 2})
+    assert response.status_code == 200
+    body = response.json()
+    assert len(body["entries"]) == 2
+    assert body["next_cursor"]
+
+    response = client.get("/audit", params={"entity_id": user_ids[0], "limit": 2, "cursor": body["next_cursor"]})
+    assert response.status_code == 200
+    rest = response.json()["entries"]
+    assert [e["operation"] for e in rest] == ["pr.create", "team.create"]
+
+    response = client.get("/audit", params={"actor": get_random_name()})
+    assert response.status_code == 200
+    assert response.json()["entries"] == []
+
+    response = client.get("/audit", params={"entity_type": "repository"})
+    assert response.status_code == 400
@@ -0,0 +1,107 @@
+import base64
+import hashlib
+import hmac
+import json
+import time
+
+import httpx
+
+from conftest import USER_TOKEN_SUBJECT, get_random_name, get_random_pr_id, get_random_user_id
+
+
+def make_jwt(claims: dict, secret: str = "dev-jwt-secret") -> str:
+    def encode(data: bytes) -> str:
+        return base64.urlsafe_b64encode(data).rstrip(b"=").decode()
+
+    header = encode(json.dumps({"alg": "HS256", "typ": "JWT"}).encode())
+    payload = encode(json.dumps(claims).encode())
+    signature = hmac.new(secret.encode(), f"{header}.{payload}".encode(), hashlib.sha256).digest()
+    return f"{header}.{payload}.{encode(signature)}"
+
+
+def test_missing_token_is_rejected(anonymous_client: httpx.Client):
+    response = anonymous_client.get("/pullRequest/list")
+    assert response.status_code == 401
+    assert response.json()["error"]["code"] == "UNAUTHORIZED"
+    assert response.headers["WWW-Authenticate"].startswith("Bearer")
+
+
+def test_invalid_token_is_rejected(anonymous_client: httpx.Client):
+    response = anonymous_client.get("/pullRequest/list", headers={"Authorization": "Bearer nope"})
+    assert response.status_code == 401
+
+
+def test_user_token_cannot_manage(user_client: httpx.Client):
+    response = user_client.post("/users/setIsActive", json={"user_id": USER_TOKEN_SUBJECT, "is_active": False})
+    assert response.status_code == 403
+    assert response.json()["error"]["code"] == "FORBIDDEN"
+
+    response = user_client.post("/pullRequest/merge", json={"pull_request_id": "pr-1"})
+    assert response.status_code == 403
+
+    response = user_client.get("/pullRequest/list")
+    assert response.status_code == 200
+
+
+def test_get_review_self_or_admin(client: httpx.Client, user_client: httpx.Client):
+    response = user_client.get("/users/getReview", params={"user_id": USER_TOKEN_SUBJECT})
+    assert response.status_code != 403
+
+    other = get_random_user_id()
+    response = user_client.get("/users/getReview", params={"user_id": other})
+    assert response.status_code == 403
+
+    response = client.get("/users/getReview", params={"user_id": other})
+    assert response.status_code != 403
+
+
+def test_pull_request_changes_require_participant(client: httpx.Client, user_client: httpx.Client):
+    author, reviewer = get_random_user_id(), get_random_user_id()
+    client.post("/team/add", json={"team_name": get_random_name(), "members": [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": reviewer, "username": "reviewer", "is_active": True},
+    ]})
+    pr_id = get_random_pr_id()
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": pr_id, "pull_request_name": "PR", "author_id": author})
+    assert response.status_code == 201
+
+    for path, payload in [
+        ("/pullRequest/create", {"pull_request_id": get_random_pr_id(), "pull_request_name": "PR", "author_id": author}),
+        ("/pullRequest/close", {"pull_request_id": pr_id}),
+        ("/pullRequest/reassign", {"pull_request_id": pr_id, "old_user_id": reviewer}),
+        ("/pullRequest/review", {"pull_request_id": pr_id, "reviewer_id": reviewer, "state": "APPROVED"}),
+    ]:
+        response = user_client.post(path, json=payload)
+        assert response.status_code == 403, path
+        assert response.json()["error"]["code"] == "FORBIDDEN"
+
+    response = client.post("/pullRequest/close", json={"pull_request_id": pr_id})
+    assert response.status_code == 200
+
+
+def test_jwt_roles(anonymous_client: httpx.Client):
+    now = int(time.time())
+    admin = make_jwt({"sub": "ci-bot", "role": "admin", "exp": now + 60})
+    response = anonymous_client.get("/audit", headers={"Authorization": f"Bearer {admin}"})
+    assert response.status_code == 200
+
+    user = make_jwt({"sub": "u42", "role": "user", "exp": now + 60})
+    response = anonymous_client.get("/audit", headers={"Authorization": f"Bearer {user}"})
+    assert response.status_code == 403
+
+    expired = make_jwt({"sub": "ci-bot", "role": "admin", "exp": now - 60})
+    response = anonymous_client.get("/audit", headers={"Authorization": f"Bearer {expired}"})
+    assert response.status_code == 401
+
+    forged = make_jwt({"sub": "ci-bot", "role": "admin", "exp": now + 60}, secret="wrong")
+    response = anonymous_client.get("/audit", headers={"Authorization": f"Bearer {forged}"})
+    assert response.status_code == 401
+
+
+def test_rate_limit_headers(user_client: httpx.Client):
+    response = user_client.get("/pullRequest/list")
+    assert response.status_code == 200
+    assert int(response.headers["X-RateLimit-Limit"]) > 0
+    assert "X-RateLimit-Remaining" in response.headers
+    assert "X-RateLimit-Reset" in response.headers
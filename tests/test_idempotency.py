@@ -0,0 +1,49 @@
+import httpx
+
+from conftest import get_random_name, get_random_pr_id
+from test_pull_request import create_team, create_pr
+
+
+def test_reassign_retry_is_replayed(client: httpx.Client):
+    team_name, user_ids = create_team(client, 4)
+    pr = create_pr(client, user_ids[0]).json()["pr"]
+    payload = {"pull_request_id": pr["pull_request_id"], "old_user_id": pr["assigned_reviewers"][0]}
+    headers = {"Idempotency-Key": get_random_name()}
+
+    first = client.post("/pullRequest/reassign", json=payload, headers=headers)
+    assert first.status_code == 200
+
+    retry = client.post("/pullRequest/reassign", json=payload, headers=headers)
+    assert retry.status_code == 200
+    assert retry.headers["Idempotent-Replayed"] == "true"
+    assert retry.json() == first.json()
+
+    response = client.get("/audit", params={"entity_id": pr["pull_request_id"]})
+    assert [e["operation"] for e in response.json()["entries"]].count("pr.reassign") == 1
+
+
+def test_create_retry_does_not_conflict(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    headers = {"Idempotency-Key": get_random_name()}
+    payload = {"pull_request_id": get_random_pr_id(), "pull_request_name": "retry", "author_id": user_ids[0]}
+
+    first = client.post("/pullRequest/create", json=payload, headers=headers)
+    assert first.status_code == 201
+    retry = client.post("/pullRequest/create", json=payload, headers=headers)
+    assert retry.status_code == 201
+    assert retry.json() == first.json()
+
+
+def test_key_reuse_with_different_body(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    headers = {"Idempotency-Key": get_random_name()}
+
+    response = create_pr(client, user_ids[0], pull_request_name="first")
+    assert response.status_code == 201
+
+    first = client.post("/pullRequest/close", json={"pull_request_id": response.json()["pr"]["pull_request_id"]},
+                        headers=headers)
+    assert first.status_code == 200
+    other = client.post("/pullRequest/close", json={"pull_request_id": "pr-1"}, headers=headers)
+    assert other.status_code == 422
+    assert other.json()["error"]["code"] == "IDEMPOTENCY_KEY_REUSED"
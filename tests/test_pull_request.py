@@ -0,0 +1,229 @@
+from concurrent.futures import ThreadPoolExecutor
+from datetime import datetime
+
+import pytest
+import httpx
+
+from conftest import get_random_name, get_random_user_id, get_random_pr_id
+
+
+def create_team(client: httpx.Client, size: int):
+    team_name = get_random_name()
+    members = [
+        {"user_id": get_random_user_id(), "username": f"user{i}", "is_active": True}
+        for i in range(size)
+    ]
+    response = client.post(
+        "/team/add", json={"team_name": team_name, "members": members})
+    assert response.status_code == 201
+    return team_name, [m["user_id"] for m in members]
+
+
+def create_pr(client: httpx.Client, author_id: str, **kwargs):
+    payload = {
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author_id,
+    }
+    payload.update(kwargs)
+    return client.post("/pullRequest/create", json=payload)
+
+
+def parse_time(value: str):
+    return datetime.fromisoformat(value.replace("Z", "+00:00"))
+
+
+def test_pr_list_pagination(client: httpx.Client):
+    team_name, user_ids = create_team(client, 3)
+    created = []
+    for _ in range(3):
+        response = create_pr(client, user_ids[0])
+        assert response.status_code == 201
+        created.append(response.json()["pr"]["pull_request_id"])
+
+    seen = []
+    cursor = None
+    while True:
+        params = {"team_name": team_name, "limit": 2, "sort_by": "created_at"}
+        if cursor:
+            params["cursor"] = cursor
+        response = client.get("/pullRequest/list", params=params)
+        assert response.status_code == 200
+        body = response.json()
+        seen.extend(pr["pull_request_id"] for pr in body["pull_requests"])
+        cursor = body.get("next_cursor")
+        if not cursor:
+            break
+
+    assert seen == created
+
+
+def test_pr_list_filter_by_reviewer(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    response = create_pr(client, user_ids[0])
+    assert response.status_code == 201
+    pr_id = response.json()["pr"]["pull_request_id"]
+
+    response = client.get("/pullRequest/list",
+                          params={"reviewer_id": user_ids[1], "status": "OPEN"})
+    assert response.status_code == 200
+    assert [pr["pull_request_id"] for pr in response.json()["pull_requests"]] == [pr_id]
+
+
+def test_pr_list_invalid_cursor(client: httpx.Client):
+    response = client.get("/pullRequest/list", params={"cursor": "garbage"})
+    assert response.status_code == 400
+    assert response.json()["error"]["code"] == "INVALID_CURSOR"
+
+
+def test_pr_timestamps_are_persisted(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    response = create_pr(client, user_ids[0])
+    assert response.status_code == 201
+    pr = response.json()["pr"]
+    assert pr["createdAt"]
+    assert parse_time(pr["updatedAt"]) == parse_time(pr["createdAt"])
+
+    response = client.post("/pullRequest/merge",
+                           json={"pull_request_id": pr["pull_request_id"]})
+    assert response.status_code == 200
+    merged = response.json()["pr"]
+    assert parse_time(merged["createdAt"]) == parse_time(pr["createdAt"])
+    assert merged["mergedAt"]
+    assert parse_time(merged["updatedAt"]) == parse_time(merged["mergedAt"])
+
+    response = client.get("/users/getReview", params={"user_id": user_ids[1]})
+    assert response.status_code == 200
+    reviews = response.json()["pull_requests"]
+    assert parse_time(reviews[0]["createdAt"]) == parse_time(pr["createdAt"])
+    assert parse_time(reviews[0]["mergedAt"]) == parse_time(merged["mergedAt"])
+    assert parse_time(reviews[0]["updatedAt"]) == parse_time(merged["updatedAt"])
+
+
+def test_pr_merge_is_idempotent(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    pr_id = create_pr(client, user_ids[0]).json()["pr"]["pull_request_id"]
+
+    first = client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    second = client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    assert first.status_code == 200
+    assert second.status_code == 200
+    assert second.json()["pr"]["mergedAt"] == first.json()["pr"]["mergedAt"]
+
+    response = client.post("/pullRequest/close", json={"pull_request_id": pr_id})
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "INVALID_TRANSITION"
+
+
+def test_pr_close_and_reopen(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    pr_id = create_pr(client, user_ids[0]).json()["pr"]["pull_request_id"]
+
+    response = client.post("/pullRequest/close", json={"pull_request_id": pr_id})
+    assert response.status_code == 200
+    assert response.json()["pr"]["status"] == "CLOSED"
+
+    response = client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "INVALID_TRANSITION"
+
+    response = client.post("/pullRequest/reopen", json={"pull_request_id": pr_id})
+    assert response.status_code == 200
+    assert response.json()["pr"]["status"] == "OPEN"
+
+
+def test_pr_merge_requires_approvals(client: httpx.Client):
+    team_name, user_ids = create_team(client, 2)
+    response = client.post("/team/settings", json={
+        "team_name": team_name,
+        "required_approvals": 1,
+    })
+    assert response.status_code == 200
+
+    pr = create_pr(client, user_ids[0]).json()["pr"]
+    pr_id = pr["pull_request_id"]
+    assert pr["reviews"] == [{"user_id": user_ids[1], "state": "PENDING"}]
+
+    response = client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "NOT_ENOUGH_APPROVALS"
+
+    response = client.post("/pullRequest/review", json={
+        "pull_request_id": pr_id,
+        "reviewer_id": user_ids[1],
+        "state": "APPROVED",
+    })
+    assert response.status_code == 200
+    assert response.json()["pr"]["reviews"][0]["state"] == "APPROVED"
+
+    response = client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    assert response.status_code == 200
+
+
+def test_pr_review_not_assigned(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    pr_id = create_pr(client, user_ids[0]).json()["pr"]["pull_request_id"]
+
+    response = client.post("/pullRequest/review", json={
+        "pull_request_id": pr_id,
+        "reviewer_id": user_ids[0],
+        "state": "APPROVED",
+    })
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "NOT_ASSIGNED"
+
+
+def test_pr_create_with_explicit_team(client: httpx.Client):
+    primary_team, primary_ids = create_team(client, 3)
+    author_id = primary_ids[0]
+
+    other_team = get_random_name()
+    other_ids = [get_random_user_id(), get_random_user_id()]
+    members = [{"user_id": author_id, "username": "user0", "is_active": True}] + [
+        {"user_id": user_id, "username": "other", "is_active": True}
+        for user_id in other_ids
+    ]
+    response = client.post(
+        "/team/add", json={"team_name": other_team, "members": members})
+    assert response.status_code == 201
+
+    response = create_pr(client, author_id, team_name=other_team)
+    assert response.status_code == 201
+    pr = response.json()["pr"]
+    assert pr["team_name"] == other_team
+    assert set(pr["assigned_reviewers"]) == set(other_ids)
+
+    response = create_pr(client, author_id)
+    assert response.status_code == 201
+    pr = response.json()["pr"]
+    assert pr["team_name"] == primary_team
+    assert set(pr["assigned_reviewers"]) <= set(primary_ids[1:])
+
+    response = client.post(
+        "/users/setIsActive", json={"user_id": author_id, "is_active": True})
+    assert response.status_code == 200
+    user = response.json()["user"]
+    assert user["team_name"] == primary_team
+    assert user["teams"] == [primary_team, other_team]
+
+
+def test_pr_create_not_member_of_team(client: httpx.Client):
+    _, user_ids = create_team(client, 2)
+    other_team, _ = create_team(client, 2)
+
+    response = create_pr(client, user_ids[0], team_name=other_team)
+    assert response.status_code == 400
+    assert response.json()["error"]["code"] == "NOT_MEMBER"
+
+
+def test_pr_create_concurrent_same_id(client: httpx.Client):
+    _, user_ids = create_team(client, 3)
+    pr_id = get_random_pr_id()
+
+    with ThreadPoolExecutor(max_workers=2) as pool:
+        responses = list(pool.map(
+            lambda _: create_pr(client, user_ids[0], pull_request_id=pr_id), range(2)))
+
+    assert sorted(r.status_code for r in responses) == [201, 409]
+    conflict = next(r for r in responses if r.status_code == 409)
+    assert conflict.json()["error"]["code"] == "PR_EXISTS"
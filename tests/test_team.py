@@ -1,7 +1,7 @@
 import pytest
 import httpx
 
-from conftest import get_random_name
+from conftest import get_random_name, get_random_user_id, get_random_pr_id
 
 
 def test_team_add_and_get_success(client: httpx.Client):
@@ -41,3 +41,247 @@ def test_team_get_not_found(client: httpx.Client):
         "/team/get", params={"team_name": get_random_name()})
     assert response.status_code == 404
     assert response.json()["error"]["code"] == "NOT_FOUND"
+
+
+def test_team_settings_default_and_update(client: httpx.Client):
+    team_name = get_random_name()
+    members = [{"user_id": "u1", "username": "user1", "is_active": True}]
+    client.post("/team/add", json={"team_name": team_name, "members": members})
+
+    response = client.get("/team/settings", params={"team_name": team_name})
+    assert response.status_code == 200
+    settings = response.json()
+    assert settings["max_reviewers"] == 2
+    assert settings["reviewer_strategy"] == "least_loaded"
+
+    response = client.post("/team/settings", json={
+        "team_name": team_name,
+        "max_reviewers": 3,
+        "reviewer_strategy": "random",
+    })
+    assert response.status_code == 200
+    settings = response.json()["settings"]
+    assert settings["max_reviewers"] == 3
+    assert settings["reviewer_strategy"] == "random"
+
+
+def test_team_settings_invalid(client: httpx.Client):
+    team_name = get_random_name()
+    members = [{"user_id": "u1", "username": "user1", "is_active": True}]
+    client.post("/team/add", json={"team_name": team_name, "members": members})
+
+    response = client.post("/team/settings", json={
+        "team_name": team_name,
+        "min_reviewers": 3,
+        "max_reviewers": 1,
+    })
+    assert response.status_code == 400
+    assert response.json()["error"]["code"] == "INVALID_SETTINGS"
+
+
+def test_team_deactivate_users_reassigns_reviews(client: httpx.Client):
+    team_name = get_random_name()
+    author, first, second, spare = (get_random_user_id() for _ in range(4))
+    members = [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": first, "username": "first", "is_active": True},
+        {"user_id": second, "username": "second", "is_active": True},
+        {"user_id": spare, "username": "spare", "is_active": False},
+    ]
+    client.post("/team/add", json={"team_name": team_name, "members": members})
+
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+    pr_id = response.json()["pr"]["pull_request_id"]
+
+    client.post("/users/setIsActive", json={"user_id": spare, "is_active": True})
+
+    response = client.post("/team/deactivateUsers", json={
+        "team_name": team_name,
+        "user_ids": [first],
+    })
+    assert response.status_code == 200
+    body = response.json()
+    assert body["deactivated_users"] == [first]
+    assert len(body["pull_requests"]) == 1
+    report = body["pull_requests"][0]
+    assert report["pull_request_id"] == pr_id
+    assert report["replacements"] == [{"old_user_id": first, "new_user_id": spare}]
+    assert report["no_candidate"] == []
+    assert set(report["assigned_reviewers"]) == {second, spare}
+
+
+def test_team_deactivate_users_not_member(client: httpx.Client):
+    team_name = get_random_name()
+    members = [{"user_id": get_random_user_id(), "username": "user", "is_active": True}]
+    client.post("/team/add", json={"team_name": team_name, "members": members})
+
+    response = client.post("/team/deactivateUsers", json={
+        "team_name": team_name,
+        "user_ids": [get_random_user_id()],
+    })
+    assert response.status_code == 400
+    assert response.json()["error"]["code"] == "NOT_MEMBER"
+
+
+def test_team_members_add_and_rename(client: httpx.Client):
+    team_name = get_random_name()
+    first, newcomer = get_random_user_id(), get_random_user_id()
+    client.post("/team/add", json={"team_name": team_name, "members": [
+        {"user_id": first, "username": "first", "is_active": True},
+    ]})
+
+    response = client.post("/team/members/add", json={
+        "team_name": team_name,
+        "members": [
+            {"user_id": first, "username": "renamed", "is_active": True},
+            {"user_id": newcomer, "username": "newcomer", "is_active": True},
+        ],
+    })
+    assert response.status_code == 200
+    members = {m["user_id"]: m["username"] for m in response.json()["team"]["members"]}
+    assert members == {first: "renamed", newcomer: "newcomer"}
+
+    new_name = get_random_name()
+    response = client.patch("/team/rename", json={
+        "team_name": team_name, "new_team_name": new_name})
+    assert response.status_code == 200
+    assert response.json()["team"]["team_name"] == new_name
+
+    response = client.get("/team/get", params={"team_name": team_name})
+    assert response.status_code == 404
+    response = client.get("/team/get", params={"team_name": new_name})
+    assert response.status_code == 200
+
+
+def test_team_members_remove_with_open_reviews(client: httpx.Client):
+    team_name = get_random_name()
+    author, reviewer, spare = (get_random_user_id() for _ in range(3))
+    client.post("/team/add", json={"team_name": team_name, "members": [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": reviewer, "username": "reviewer", "is_active": True},
+    ]})
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+    pr_id = response.json()["pr"]["pull_request_id"]
+
+    response = client.post("/team/members/remove", json={
+        "team_name": team_name, "user_ids": [reviewer]})
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "HAS_OPEN_REVIEWS"
+
+    client.post("/team/members/add", json={"team_name": team_name, "members": [
+        {"user_id": spare, "username": "spare", "is_active": True},
+    ]})
+    response = client.post("/team/members/remove", json={
+        "team_name": team_name,
+        "user_ids": [reviewer],
+        "reassign_open_reviews": True,
+    })
+    assert response.status_code == 200
+    body = response.json()
+    assert body["removed_users"] == [reviewer]
+    assert body["pull_requests"][0]["pull_request_id"] == pr_id
+    assert body["pull_requests"][0]["assigned_reviewers"] == [spare]
+    assert {m["user_id"] for m in body["team"]["members"]} == {author, spare}
+
+
+def test_team_delete(client: httpx.Client):
+    team_name = get_random_name()
+    author, reviewer = get_random_user_id(), get_random_user_id()
+    client.post("/team/add", json={"team_name": team_name, "members": [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": reviewer, "username": "reviewer", "is_active": True},
+    ]})
+    pr_id = get_random_pr_id()
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": pr_id,
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+
+    response = client.delete("/team", params={"team_name": team_name})
+    assert response.status_code == 409
+    assert response.json()["error"]["code"] == "TEAM_HAS_OPEN_PRS"
+
+    client.post("/pullRequest/merge", json={"pull_request_id": pr_id})
+    response = client.delete("/team", params={"team_name": team_name})
+    assert response.status_code == 204
+
+    response = client.get("/team/get", params={"team_name": team_name})
+    assert response.status_code == 404
+
+
+def test_team_sync_dry_run_and_apply(client: httpx.Client):
+    team_name = get_random_name()
+    author, leaver, sleeper, newcomer = (get_random_user_id() for _ in range(4))
+    client.post("/team/add", json={"team_name": team_name, "members": [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": leaver, "username": "leaver", "is_active": True},
+        {"user_id": sleeper, "username": "sleeper", "is_active": False},
+    ]})
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+    pr_id = response.json()["pr"]["pull_request_id"]
+
+    roster = {
+        "team_name": team_name,
+        "members": [
+            {"user_id": author, "username": "author", "is_active": True},
+            {"user_id": sleeper, "username": "sleeper", "is_active": True},
+            {"user_id": newcomer, "username": "newcomer", "is_active": True},
+        ],
+    }
+
+    response = client.put("/team/sync", json={**roster, "dry_run": True})
+    assert response.status_code == 200
+    diff = response.json()
+    assert diff["dry_run"] is True
+    assert diff["created"] is False
+    assert diff["added"] == [newcomer]
+    assert diff["removed"] == [leaver]
+    assert diff["reactivated"] == [sleeper]
+    assert diff["pull_requests"][0]["pull_request_id"] == pr_id
+    assert "team" not in diff
+
+    response = client.get("/team/get", params={"team_name": team_name})
+    assert {m["user_id"] for m in response.json()["members"]} == {author, leaver, sleeper}
+
+    response = client.put("/team/sync", json=roster)
+    assert response.status_code == 200
+    body = response.json()
+    assert body["removed"] == [leaver]
+    members = {m["user_id"]: m["is_active"] for m in body["team"]["members"]}
+    assert members == {author: True, sleeper: True, newcomer: True}
+    assigned = body["pull_requests"][0]["assigned_reviewers"]
+    assert leaver not in assigned and len(assigned) == 1
+
+    response = client.put("/team/sync", json=roster)
+    body = response.json()
+    assert body["added"] == body["removed"] == body["reactivated"] == []
+
+
+def test_team_sync_creates_team(client: httpx.Client):
+    team_name = get_random_name()
+    user_id = get_random_user_id()
+    response = client.put("/team/sync", json={"team_name": team_name, "members": [
+        {"user_id": user_id, "username": "user", "is_active": True},
+    ]})
+    assert response.status_code == 200
+    body = response.json()
+    assert body["created"] is True
+    assert body["added"] == [user_id]
+    assert body["team"]["team_name"] == team_name
@@ -1,7 +1,9 @@
 import pytest
 import httpx
 
-from conftest import get_random_name
+from datetime import datetime, timedelta, timezone
+
+from conftest import get_random_name, get_random_user_id, get_random_pr_id
 
 
 def set_user_activity(client: httpx.Client, user_id: str, is_active: bool):
@@ -33,3 +35,92 @@ def test_user_set_is_active_not_found(client: httpx.Client):
     response = set_user_activity(client, "u666", False)
     assert response.status_code == 404
     assert response.json()["error"]["code"] == "NOT_FOUND"
+
+
+def test_user_unavailability_excludes_from_review(client: httpx.Client):
+    author, away, present = (get_random_user_id() for _ in range(3))
+    members = [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": away, "username": "away", "is_active": True},
+        {"user_id": present, "username": "present", "is_active": True},
+    ]
+    client.post("/team/add",
+                json={"team_name": get_random_name(), "members": members})
+
+    now = datetime.now(timezone.utc)
+    response = client.post("/users/unavailability", json={
+        "user_id": away,
+        "starts_at": (now - timedelta(days=1)).isoformat(),
+        "ends_at": (now + timedelta(days=1)).isoformat(),
+        "reason": "vacation",
+    })
+    assert response.status_code == 201
+    period_id = response.json()["period"]["id"]
+
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+    assert response.json()["pr"]["assigned_reviewers"] == [present]
+
+    response = client.delete("/users/unavailability",
+                             params={"user_id": away, "id": period_id})
+    assert response.status_code == 204
+
+    response = client.get("/users/unavailability", params={"user_id": away})
+    assert response.status_code == 200
+    assert response.json()["periods"] == []
+
+
+def test_user_unavailability_invalid_period(client: httpx.Client):
+    now = datetime.now(timezone.utc)
+    response = client.post("/users/unavailability", json={
+        "user_id": "u1",
+        "starts_at": now.isoformat(),
+        "ends_at": (now - timedelta(hours=1)).isoformat(),
+    })
+    assert response.status_code == 400
+    assert response.json()["error"]["code"] == "INVALID_PERIOD"
+
+
+def test_user_deactivate_reassigns_open_reviews(client: httpx.Client):
+    author, leaving, spare = (get_random_user_id() for _ in range(3))
+    members = [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": leaving, "username": "leaving", "is_active": True},
+        {"user_id": spare, "username": "spare", "is_active": True},
+    ]
+    client.post("/team/add",
+                json={"team_name": get_random_name(), "members": members})
+
+    now = datetime.now(timezone.utc)
+    period_id = client.post("/users/unavailability", json={
+        "user_id": spare,
+        "starts_at": (now - timedelta(days=1)).isoformat(),
+        "ends_at": (now + timedelta(days=1)).isoformat(),
+    }).json()["period"]["id"]
+
+    response = client.post("/pullRequest/create", json={
+        "pull_request_id": get_random_pr_id(),
+        "pull_request_name": get_random_name(),
+        "author_id": author,
+    })
+    assert response.status_code == 201
+    pr_id = response.json()["pr"]["pull_request_id"]
+    assert response.json()["pr"]["assigned_reviewers"] == [leaving]
+
+    client.delete("/users/unavailability",
+                  params={"user_id": spare, "id": period_id})
+
+    response = client.post("/users/setIsActive", json={
+        "user_id": leaving,
+        "is_active": False,
+        "reassign_open_reviews": True,
+    })
+    assert response.status_code == 200
+    reassignment = response.json()["reassignment"]
+    assert reassignment["reassigned"] == [
+        {"pull_request_id": pr_id, "old_user_id": leaving, "new_user_id": spare}]
+    assert reassignment["no_candidate"] == []
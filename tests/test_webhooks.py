@@ -0,0 +1,107 @@
+import random
+import uuid
+
+import httpx
+
+from conftest import get_random_name, get_random_user_id
+
+GITLAB_TOKEN = "dev-gitlab-token"
+
+
+def send_gitlab_event(client: httpx.Client, action: str, project_id: int, iid: int,
+                      username: str, event_uuid: str, title: str = "Add search"):
+    payload = {
+        "object_kind": "merge_request",
+        "user": {"username": username},
+        "project": {"id": project_id},
+        "object_attributes": {"iid": iid, "title": title, "action": action},
+    }
+    return client.post("/webhooks/gitlab", json=payload, headers={
+        "X-Gitlab-Token": GITLAB_TOKEN,
+        "X-Gitlab-Event": "Merge Request Hook",
+        "X-Gitlab-Event-UUID": event_uuid,
+    })
+
+
+def test_gitlab_webhook_lifecycle(client: httpx.Client):
+    author, reviewer = get_random_user_id(), get_random_user_id()
+    client.post("/team/add", json={"team_name": get_random_name(), "members": [
+        {"user_id": author, "username": "author", "is_active": True},
+        {"user_id": reviewer, "username": "reviewer", "is_active": True},
+    ]})
+
+    username = get_random_name()
+    response = client.post("/webhooks/gitlab/users", json={
+        "username": username, "user_id": author})
+    assert response.status_code == 200
+
+    project_id = random.randint(100_000, 2_000_000_000)
+    iid = 7
+    open_uuid = str(uuid.uuid4())
+    response = send_gitlab_event(client, "open", project_id, iid, username, open_uuid)
+    assert response.status_code == 200
+    body = response.json()
+    assert body["status"] == "processed"
+    assert body["pr"]["author_id"] == author
+    pr_id = body["pr"]["pull_request_id"]
+
+    response = send_gitlab_event(client, "open", project_id, iid, username, open_uuid)
+    assert response.json()["status"] == "ignored"
+
+    # тот же iid в другом проекте - другой PR
+    response = send_gitlab_event(client, "open", project_id + 1, iid, username,
+                                 str(uuid.uuid4()))
+    assert response.json()["status"] == "processed"
+    assert response.json()["pr"]["pull_request_id"] != pr_id
+
+    response = send_gitlab_event(client, "update", project_id, iid, username,
+                                 str(uuid.uuid4()), title="Add search v2")
+    assert response.json()["pr"]["pull_request_id"] == pr_id
+    assert response.json()["pr"]["pull_request_name"] == "Add search v2"
+
+    response = send_gitlab_event(client, "merge", project_id, iid, username, str(uuid.uuid4()))
+    assert response.status_code == 200
+    assert response.json()["pr"]["status"] == "MERGED"
+
+    response = client.delete("/webhooks/gitlab/users", params={"username": username})
+    assert response.status_code == 204
+
+
+def test_gitlab_webhook_invalid_token(client: httpx.Client):
+    response = client.post("/webhooks/gitlab", json={}, headers={
+        "X-Gitlab-Token": "wrong",
+        "X-Gitlab-Event": "Merge Request Hook",
+    })
+    assert response.status_code == 401
+    assert response.json()["error"]["code"] == "INVALID_SIGNATURE"
+
+
+def test_webhook_subscriptions_crud(client: httpx.Client):
+    url = f"http://localhost:9/{get_random_name()}"
+    response = client.post("/webhooks/subscriptions", json={
+        "url": url,
+        "secret": "s3cret",
+        "event_types": ["pr.created", "reviewer.assigned"],
+    })
+    assert response.status_code == 201
+    subscription = response.json()["subscription"]
+    assert subscription["event_types"] == ["pr.created", "reviewer.assigned"]
+    assert "secret" not in subscription
+
+    response = client.get("/webhooks/subscriptions")
+    assert response.status_code == 200
+    assert subscription["id"] in [s["id"] for s in response.json()["subscriptions"]]
+
+    response = client.delete("/webhooks/subscriptions", params={"id": subscription["id"]})
+    assert response.status_code == 204
+    response = client.delete("/webhooks/subscriptions", params={"id": subscription["id"]})
+    assert response.status_code == 404
+
+
+def test_webhook_subscription_unknown_event(client: httpx.Client):
+    response = client.post("/webhooks/subscriptions", json={
+        "url": "http://localhost:9/hook",
+        "secret": "s3cret",
+        "event_types": ["pr.deleted"],
+    })
+    assert response.status_code == 400